@@ -0,0 +1,223 @@
+// Package analysis 提供检查 gslog 日志调用的静态分析器 可通过 go vet -vettool 或 cmd/gslogvet 运行
+// 独立模块 gslog/analysis 避免 gslog 依赖 golang.org/x/tools
+//
+// 检查内容
+//   - key/value 参数个数为奇数
+//   - key 不是常量字符串
+//   - 同一次调用中重复的 key
+//   - key 不符合命名规范 默认 snake_case 可通过 -key-pattern 修改
+//   - 同一次调用中混用 LogField 以及 key/value
+//   - 结构化日志消息中包含 Printf 格式化占位符
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	// gslog 包路径
+	gslogPackagePath = "gslog"
+	// 默认 key 命名规范 snake_case
+	defaultKeyPattern = `^[a-z0-9]+(_[a-z0-9]+)*$`
+	// 消息参数名
+	messageParamName = "msg"
+)
+
+var (
+	// Printf 格式化占位符 %% 不算
+	printfVerbRegexp = regexp.MustCompile(`%[-+# 0]*(\d+|\*)?(\.(\d+|\*))?[vTtbcdoOqxXUeEfFgGsp]`)
+	// key 命名规范
+	keyPattern string
+	// gslog 结构化日志函数以及 Logger 方法 Printf 风格的 Xf 方法不在其中
+	structuredLogFuncs = map[string]bool{
+		"Trace": true, "Debug": true, "Info": true, "Warn": true, "Error": true, "Panic": true, "Fatal": true,
+		"TraceContext": true, "DebugContext": true, "InfoContext": true, "WarnContext": true,
+		"ErrorContext": true, "PanicContext": true, "FatalContext": true,
+		"Tracew": true, "Debugw": true, "Infow": true, "Warnw": true, "Errorw": true, "Panicw": true, "Fatalw": true,
+		"Log": true,
+	}
+)
+
+// Analyzer 检查 gslog 结构化日志调用
+var Analyzer = &analysis.Analyzer{
+	Name:     "gslogvet",
+	Doc:      "check gslog structured logging calls for malformed key/value arguments",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func init() {
+	Analyzer.Flags.StringVar(&keyPattern, "key-pattern", defaultKeyPattern,
+		"regular expression log keys must match, empty disables the check")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	var keyRegexp *regexp.Regexp
+	if keyPattern != "" {
+		var err error
+		if keyRegexp, err = regexp.Compile(keyPattern); err != nil {
+			return nil, fmt.Errorf("invalid -key-pattern: %w", err)
+		}
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok {
+			return
+		}
+		msgIndex, ok := structuredLogCall(fn)
+		if !ok || len(call.Args) <= msgIndex {
+			return
+		}
+		checkMessage(pass, fn, call.Args[msgIndex])
+		// args... 透传无法检查
+		if call.Ellipsis.IsValid() {
+			return
+		}
+		checkArgs(pass, fn, call.Args[msgIndex+1:], keyRegexp)
+	})
+	return nil, nil
+}
+
+// structuredLogCall 判断是否为 gslog 结构化日志调用 (..., msg string, args ...any) 返回 msg 参数下标
+// 只检查 structuredLogFuncs 中的函数 其余签名相同的函数不属于结构化日志调用
+func structuredLogCall(fn *types.Func) (int, bool) {
+	if fn.Pkg() == nil || fn.Pkg().Path() != gslogPackagePath || !structuredLogFuncs[fn.Name()] {
+		return 0, false
+	}
+	sig := fn.Type().(*types.Signature)
+	params := sig.Params()
+	if !sig.Variadic() || params.Len() < 2 {
+		return 0, false
+	}
+	slice, ok := params.At(params.Len() - 1).Type().(*types.Slice)
+	if !ok || !isEmptyInterface(slice.Elem()) {
+		return 0, false
+	}
+	msg := params.At(params.Len() - 2)
+	if msg.Name() != messageParamName || !isString(msg.Type()) {
+		return 0, false
+	}
+	return params.Len() - 2, true
+}
+
+// checkMessage 检查消息是否包含 Printf 占位符
+func checkMessage(pass *analysis.Pass, fn *types.Func, msg ast.Expr) {
+	value, ok := constantString(pass, msg)
+	if !ok {
+		return
+	}
+	if verb := printfVerbRegexp.FindString(strings.ReplaceAll(value, "%%", "")); verb != "" {
+		pass.Reportf(msg.Pos(), "%s message contains Printf verb %s, structured messages are not formatted; use the f variant or pass values as fields",
+			fn.Name(), verb)
+	}
+}
+
+// checkArgs 检查 key/value 参数
+func checkArgs(pass *analysis.Pass, fn *types.Func, args []ast.Expr, keyRegexp *regexp.Regexp) {
+	var hasField, hasPair bool
+	seen := make(map[string]bool)
+	checkKey := func(expr ast.Expr, key string) {
+		if seen[key] {
+			pass.Reportf(expr.Pos(), "%s has duplicate key %q", fn.Name(), key)
+		}
+		seen[key] = true
+		if keyRegexp != nil && !keyRegexp.MatchString(key) {
+			pass.Reportf(expr.Pos(), "%s key %q does not match naming convention %s", fn.Name(), key, keyRegexp)
+		}
+	}
+
+	for idx := 0; idx < len(args); idx++ {
+		arg := args[idx]
+		argType := pass.TypesInfo.TypeOf(arg)
+		if isLogField(argType) {
+			hasField = true
+			if key, ok := fieldKey(pass, arg); ok {
+				checkKey(arg, key)
+			}
+			continue
+		}
+
+		hasPair = true
+		if !isString(argType) {
+			pass.Reportf(arg.Pos(), "%s key is not a string: %s", fn.Name(), types.ExprString(arg))
+			// 下一个参数依旧当作 key 处理 与运行时保持一致
+			continue
+		}
+		if idx == len(args)-1 {
+			pass.Reportf(arg.Pos(), "%s has odd number of key/value arguments, missing value for key %s",
+				fn.Name(), types.ExprString(arg))
+			break
+		}
+		if key, ok := constantString(pass, arg); ok {
+			checkKey(arg, key)
+		} else {
+			pass.Reportf(arg.Pos(), "%s key %s is not a constant string", fn.Name(), types.ExprString(arg))
+		}
+		// 跳过 value
+		idx++
+	}
+
+	if hasField && hasPair {
+		pass.Reportf(args[0].Pos(), "%s mixes LogField and key/value arguments", fn.Name())
+	}
+}
+
+// fieldKey 获取 gslog.String("key", val) 等字段构造函数的常量 key
+func fieldKey(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", false
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != gslogPackagePath {
+		return "", false
+	}
+	if sig := fn.Type().(*types.Signature); sig.Recv() != nil || sig.Params().Len() == 0 || sig.Params().At(0).Name() != "key" {
+		return "", false
+	}
+	return constantString(pass, call.Args[0])
+}
+
+// constantString 获取常量字符串表达式的值
+func constantString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// isLogField 判断是否为 gslog.LogField 包括 LogField 的类型别名
+func isLogField(typ types.Type) bool {
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == gslogPackagePath && obj.Name() == "LogField"
+}
+
+// isString 判断底层类型是否为 string
+func isString(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// isEmptyInterface 判断是否为 any
+func isEmptyInterface(typ types.Type) bool {
+	iface, ok := typ.Underlying().(*types.Interface)
+	return ok && iface.Empty()
+}
@@ -0,0 +1,20 @@
+// gslogvet 检查 gslog 结构化日志调用
+//
+// 直接运行
+//
+//	gslogvet ./...
+//
+// 或者作为 go vet 的分析工具
+//
+//	go vet -vettool=$(which gslogvet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"gslog/analysis"
+)
+
+func main() {
+	singlechecker.Main(analysis.Analyzer)
+}
@@ -0,0 +1,54 @@
+package a
+
+import (
+	"context"
+
+	"gslog"
+)
+
+// Field LogField 别名
+type Field = gslog.LogField
+
+func oddCount(logger *gslog.Logger) {
+	gslog.Info("msg", "user_id")             // want `Info has odd number of key/value arguments, missing value for key "user_id"`
+	logger.Info("msg", "user_id", 1, "name") // want `Info has odd number of key/value arguments, missing value for key "name"`
+	logger.Infow("msg", "user_id")           // want `Infow has odd number of key/value arguments`
+}
+
+func nonStringKey(logger *gslog.Logger, key string) {
+	gslog.Info("msg", 1, "v")    // want `Info key is not a string: 1` `missing value for key "v"`
+	logger.Info("msg", key, "v") // want `Info key key is not a constant string`
+}
+
+func keyConvention() {
+	gslog.Info("msg", "userID", 1)                                          // want `Info key "userID" does not match naming convention`
+	gslog.Info("msg", "user_id", 1, "user_id", 2)                           // want `Info has duplicate key "user_id"`
+	gslog.Info("msg", gslog.String("name", "v"), gslog.String("name", "v")) // want `Info has duplicate key "name"`
+}
+
+func mixed() {
+	gslog.Info("msg", gslog.String("name", "v"), "user_id", 1) // want `Info mixes LogField and key/value arguments`
+}
+
+func formatMismatch(logger *gslog.Logger) {
+	gslog.Info("user %d logged in", "user_id", 1) // want `Info message contains Printf verb %d`
+	logger.Info("ratio %.2f", "ratio", 0.5)       // want `Info message contains Printf verb %.2f`
+}
+
+func knownMethods(ctx context.Context, logger *gslog.Logger) {
+	logger.InfoContext(ctx, "msg", "user_id")                           // want `InfoContext has odd number of key/value arguments`
+	logger.Log(ctx, gslog.InfoLevel, "msg", "user_id", 1, "user_id", 2) // want `Log has duplicate key "user_id"`
+	gslog.Leaf("user %d", "user_id")
+	gslog.Notef("user %d", "user_id")
+}
+
+func clean(logger *gslog.Logger, field Field, args []any) {
+	gslog.Info("msg")
+	gslog.Info("msg", "user_id", 1, "name", "v")
+	gslog.Info("msg", gslog.String("name", "v"), gslog.Int("count", 1))
+	gslog.Info("100%% done", "user_id", 1)
+	gslog.Info("msg", field)
+	gslog.Info("msg", args...)
+	gslog.Infof("user %d logged in", 1)
+	logger.Infof("ratio %.2f", 0.5)
+}
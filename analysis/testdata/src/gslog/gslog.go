@@ -0,0 +1,38 @@
+// Package gslog 分析器测试使用的 gslog 桩代码 仅保留被检查的函数签名
+package gslog
+
+import "context"
+
+type LogField struct {
+	Key   string
+	Value any
+}
+
+func String(key string, val string) LogField {
+	return LogField{Key: key, Value: val}
+}
+
+func Int(key string, val int) LogField {
+	return LogField{Key: key, Value: val}
+}
+
+type LogLevel int
+
+const InfoLevel LogLevel = 0
+
+type Logger struct{}
+
+func (l *Logger) Info(msg string, args ...any)                                     {}
+func (l *Logger) Infof(format string, args ...any)                                 {}
+func (l *Logger) Infow(msg string, keysAndValues ...any)                           {}
+func (l *Logger) InfoContext(ctx context.Context, msg string, args ...any)         {}
+func (l *Logger) Log(ctx context.Context, level LogLevel, msg string, args ...any) {}
+
+func Info(msg string, args ...any)     {}
+func Infof(format string, args ...any) {}
+
+// Leaf 以 f 结尾的结构化签名函数 不属于结构化日志方法集 不检查
+func Leaf(msg string, args ...any) {}
+
+// Notef 以 f 结尾 参数名同样为 msg 不属于结构化日志方法集 不检查
+func Notef(msg string, args ...any) {}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gslog"
+)
+
+// filter 日志过滤条件 所有条件同时满足才输出
+type filter struct {
+	level    *gslog.LogLevel
+	since    time.Time
+	until    time.Time
+	contains string
+	where    expr
+}
+
+// active 是否设置了过滤条件
+func (f *filter) active() bool {
+	return f.level != nil || !f.since.IsZero() || !f.until.IsZero() || f.contains != "" || f.where != nil
+}
+
+// match 日志是否满足过滤条件
+func (f *filter) match(entry *gslog.DecodedEntry) bool {
+	if f.level != nil && entry.Level < *f.level {
+		return false
+	}
+	if !f.since.IsZero() && entry.Time.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && entry.Time.After(f.until) {
+		return false
+	}
+	if f.contains != "" && !strings.Contains(entry.Msg, f.contains) {
+		return false
+	}
+	return f.where == nil || f.where.eval(entry)
+}
+
+// expr 字段表达式
+//
+//	expr    = or
+//	or      = and { ("or" | "||") and }
+//	and     = unary { ("and" | "&&") unary }
+//	unary   = ("not" | "!") unary | "(" expr ")" | compare
+//	compare = key [ ("==" | "=" | "!=" | ">" | ">=" | "<" | "<=" | "~" | "!~") value ]
+//
+// 只有 key 时判断字段是否存在 ~ 为正则匹配
+// key 优先匹配字段 支持 a.b 访问字段组 不存在时匹配 msg/level/time/file/line/function
+type expr interface {
+	eval(entry *gslog.DecodedEntry) bool
+}
+
+type andExpr struct{ left, right expr }
+
+func (a *andExpr) eval(entry *gslog.DecodedEntry) bool {
+	return a.left.eval(entry) && a.right.eval(entry)
+}
+
+type orExpr struct{ left, right expr }
+
+func (o *orExpr) eval(entry *gslog.DecodedEntry) bool {
+	return o.left.eval(entry) || o.right.eval(entry)
+}
+
+type notExpr struct{ inner expr }
+
+func (n *notExpr) eval(entry *gslog.DecodedEntry) bool {
+	return !n.inner.eval(entry)
+}
+
+// compareExpr 字段比较 op 为空时判断字段是否存在
+type compareExpr struct {
+	key   string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+func (c *compareExpr) eval(entry *gslog.DecodedEntry) bool {
+	val, ok := lookup(entry, c.key)
+	if !ok {
+		// 字段不存在只满足 !=
+		return c.op == "!="
+	}
+	switch c.op {
+	case "":
+		return true
+	case "~":
+		return c.re.MatchString(val.String())
+	case "!~":
+		return !c.re.MatchString(val.String())
+	}
+	cmp, ok := compareValue(c.key, val, c.value)
+	if !ok {
+		return c.op == "!="
+	}
+	switch c.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// compareValue 按字段类型比较 返回 -1/0/1 无法比较时返回 false
+func compareValue(key string, val gslog.LogFieldValue, text string) (int, bool) {
+	switch val.Kind() {
+	case gslog.LogFieldValueInt64, gslog.LogFieldValueUint64, gslog.LogFieldValueFloat64:
+		num, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return strings.Compare(val.String(), text), true
+		}
+		return compareOrdered(numberOf(val), num), true
+	case gslog.LogFieldValueDuration:
+		if duration, err := time.ParseDuration(text); err == nil {
+			return compareOrdered(val.Duration(), duration), true
+		}
+	case gslog.LogFieldValueTime:
+		if tm, err := parseTime(text, time.Time{}); err == nil {
+			return val.Time().Compare(tm), true
+		}
+	case gslog.LogFieldValueNil:
+		if text == "null" || text == "nil" || text == "<nil>" {
+			return 0, true
+		}
+		return 0, false
+	}
+	if key == levelKey {
+		level, err := gslog.ParseLogLevel(text)
+		current, errCurrent := gslog.ParseLogLevel(val.String())
+		if err == nil && errCurrent == nil {
+			return compareOrdered(current, level), true
+		}
+	}
+	return strings.Compare(val.String(), text), true
+}
+
+// numberOf 数值字段转换为 float64
+func numberOf(val gslog.LogFieldValue) float64 {
+	switch val.Kind() {
+	case gslog.LogFieldValueInt64:
+		return float64(val.Int64())
+	case gslog.LogFieldValueUint64:
+		return float64(val.Uint64())
+	default:
+		return val.Float64()
+	}
+}
+
+// compareOrdered 比较有序值
+func compareOrdered[T int | int64 | float64 | time.Duration | gslog.LogLevel](left, right T) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// 内置 key
+const (
+	msgKey      = "msg"
+	levelKey    = "level"
+	timeKey     = "time"
+	fileKey     = "file"
+	lineKey     = "line"
+	functionKey = "function"
+)
+
+// lookup 查找字段 支持 a.b 访问字段组 字段不存在时查找内置 key
+func lookup(entry *gslog.DecodedEntry, key string) (gslog.LogFieldValue, bool) {
+	if field, ok := entry.Field(key); ok {
+		return field.Value, true
+	}
+	if val, ok := lookupPath(entry.Fields, strings.Split(key, ".")); ok {
+		return val, true
+	}
+	switch key {
+	case msgKey:
+		return gslog.StringFieldValue(entry.Msg), true
+	case levelKey:
+		return gslog.StringFieldValue(entry.Level.String()), true
+	case timeKey:
+		return gslog.TimeFieldValue(entry.Time), !entry.Time.IsZero()
+	case fileKey:
+		return gslog.StringFieldValue(entry.File), entry.File != ""
+	case lineKey:
+		return gslog.IntFieldValue(entry.Line), entry.File != ""
+	case functionKey:
+		return gslog.StringFieldValue(entry.Function), entry.Function != ""
+	}
+	return gslog.LogFieldValue{}, false
+}
+
+// lookupPath 逐级查找字段组 重复 key 取最后一个
+func lookupPath(fields []gslog.LogField, path []string) (gslog.LogFieldValue, bool) {
+	for idx := len(fields) - 1; idx >= 0; idx-- {
+		if fields[idx].Key != path[0] {
+			continue
+		}
+		val := fields[idx].Value
+		if len(path) == 1 {
+			return val, true
+		}
+		switch val.Kind() {
+		case gslog.LogFieldValueFields:
+			return lookupPath(val.Fields(), path[1:])
+		case gslog.LogFieldValueMap:
+			return lookupPath(val.Map(), path[1:])
+		case gslog.LogFieldValueField:
+			return lookupPath([]gslog.LogField{val.Field()}, path[1:])
+		}
+		return gslog.LogFieldValue{}, false
+	}
+	return gslog.LogFieldValue{}, false
+}
+
+// token 表达式词法单元
+type token struct {
+	text   string
+	quoted bool
+}
+
+// parseExpr 解析字段表达式
+func parseExpr(text string) (expr, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return result, nil
+}
+
+// operators 比较运算符 长的在前
+var operators = []string{"==", "!=", ">=", "<=", "!~", "&&", "||", ">", "<", "~", "=", "!", "(", ")"}
+
+// tokenize 词法分析
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	for idx := 0; idx < len(text); {
+		switch ch := text[idx]; {
+		case unicode.IsSpace(rune(ch)):
+			idx++
+			continue
+		case ch == '"' || ch == '\'':
+			end := idx + 1
+			for ; end < len(text) && text[end] != ch; end++ {
+				if text[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(text) {
+				return nil, fmt.Errorf("unterminated string at %d", idx)
+			}
+			val := text[idx+1 : end]
+			if ch == '"' {
+				unquoted, err := strconv.Unquote(text[idx : end+1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid string at %d: %w", idx, err)
+				}
+				val = unquoted
+			}
+			tokens = append(tokens, token{text: val, quoted: true})
+			idx = end + 1
+			continue
+		}
+		if op := matchOperator(text[idx:]); op != "" {
+			tokens = append(tokens, token{text: op})
+			idx += len(op)
+			continue
+		}
+		end := idx
+		for end < len(text) && !unicode.IsSpace(rune(text[end])) && matchOperator(text[end:]) == "" && text[end] != '"' {
+			end++
+		}
+		tokens = append(tokens, token{text: text[idx:end]})
+		idx = end
+	}
+	return tokens, nil
+}
+
+// matchOperator 匹配运算符
+func matchOperator(text string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(text, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// parser 递归下降解析
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// peek 下一个词法单元 关键字不区分大小写
+func (p *parser) peek(keywords ...string) bool {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].quoted {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(p.tokens[p.pos].text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("or", "||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("and", "&&") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	switch {
+	case p.peek("not", "!"):
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{inner: inner}, nil
+	case p.peek("("):
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return inner, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (expr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	key := p.tokens[p.pos]
+	if !key.quoted && matchOperator(key.text) != "" {
+		return nil, fmt.Errorf("unexpected %q", key.text)
+	}
+	p.pos++
+
+	if !p.peek("==", "=", "!=", ">", ">=", "<", "<=", "~", "!~") {
+		return &compareExpr{key: key.text}, nil
+	}
+	op := p.tokens[p.pos].text
+	if op == "=" {
+		op = "=="
+	}
+	p.pos++
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("missing value after %s", op)
+	}
+	value := p.tokens[p.pos]
+	p.pos++
+
+	compare := &compareExpr{key: key.text, op: op, value: value.text}
+	if op == "~" || op == "!~" {
+		re, err := regexp.Compile(value.text)
+		if err != nil {
+			return nil, err
+		}
+		compare.re = re
+	}
+	return compare, nil
+}
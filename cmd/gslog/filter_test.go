@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"gslog"
+)
+
+// newFilterEntry 表达式测试使用的日志
+func newFilterEntry() *gslog.DecodedEntry {
+	return &gslog.DecodedEntry{
+		Time:     time.Date(2024, 6, 11, 10, 0, 0, 0, time.Local),
+		Level:    gslog.WarnLevel,
+		File:     "main.go",
+		Line:     12,
+		Function: "main.run",
+		Msg:      "request done",
+		Fields: []gslog.LogField{
+			gslog.Int("status", 500),
+			gslog.String("path", "/api/users"),
+			gslog.String("user", "a b"),
+			gslog.Duration("cost", 1500*time.Millisecond),
+			gslog.Bool("and", true),
+			gslog.Fields("req", gslog.Int("id", 7), gslog.String("method", "GET")),
+		},
+	}
+}
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		// 比较以及字段是否存在
+		{"status", true},
+		{"missing", false},
+		{"status == 500", true},
+		{"status=500", true},
+		{"status >= 500", true},
+		{"status>500", false},
+		{"status < 1e3", true},
+		{"missing != 1", true},
+		{"cost > 1s", true},
+		{"cost <= 1s", false},
+		{"req.id == 7", true},
+		{"req.method != GET", false},
+		{"level >= info", true},
+		{"level == error", false},
+		{"msg ~ ^request", true},
+		{"msg !~ done$", false},
+		{"line == 12 and function == main.run", true},
+		{"time > '2024-06-11 09:00:00'", true},
+
+		// 优先级 and 高于 or
+		{"status == 200 and path ~ api or user", true},
+		{"status == 200 and (path ~ api or user)", false},
+		{"user or status == 200 and missing", true},
+		{"(user or status == 200) and missing", false},
+		{"status==200 || path~api && user", true},
+
+		// not 只作用于紧随其后的表达式
+		{"not missing", true},
+		{"not status == 500", false},
+		{"! status == 500 or user", true},
+		{"not (status == 500 or user)", false},
+		{"NOT not status", true},
+
+		// 引号 单引号内不处理转义
+		{`user == "a b"`, true},
+		{`user == 'a b'`, true},
+		{`path ~ "^/api/"`, true},
+		{`"and" == true`, true},
+		{`msg == "request done"`, true},
+		{`path ~ '^/api/\w+$'`, true},
+	}
+	entry := newFilterEntry()
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			result, err := parseExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("parseExpr(%q): %v", tt.expr, err)
+			}
+			if got := result.eval(entry); got != tt.want {
+				t.Errorf("eval(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExprError(t *testing.T) {
+	tests := []string{
+		"",
+		"status ==",
+		"status == 500 and",
+		"(status == 500",
+		"status == 500)",
+		"== 500",
+		"not",
+		`user == "a b`,
+		`user == "\q"`,
+		"msg ~ (",
+		"status 500",
+	}
+	for _, text := range tests {
+		t.Run(text, func(t *testing.T) {
+			if _, err := parseExpr(text); err == nil {
+				t.Errorf("parseExpr(%q) error = nil, want error", text)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// 文件检查间隔
+const followInterval = 200 * time.Millisecond
+
+// follow 持续读取文件新增的日志行 文件被 LogFileRollover 轮转(重命名后新建)或截断时从新文件开头继续读取
+func follow(ctx context.Context, name string, handle func(line []byte) error) error {
+	var (
+		file    *os.File
+		info    os.FileInfo
+		reader  *bufio.Reader
+		offset  int64
+		partial []byte
+	)
+	defer func() {
+		if file != nil {
+			_ = file.Close()
+		}
+	}()
+
+	for {
+		// 等待文件创建
+		if file == nil {
+			var err error
+			if file, err = os.Open(name); err != nil {
+				if !os.IsNotExist(err) {
+					return err
+				}
+				file = nil
+			} else if info, err = file.Stat(); err != nil {
+				return err
+			} else {
+				reader, offset, partial = bufio.NewReader(file), 0, nil
+			}
+		}
+
+		// 读取至文件末尾
+		if file != nil {
+			for {
+				line, err := reader.ReadBytes('\n')
+				offset += int64(len(line))
+				if err == nil {
+					if err = handle(append(partial, line...)); err != nil {
+						return err
+					}
+					partial = nil
+					continue
+				}
+				if !errors.Is(err, io.EOF) {
+					return err
+				}
+				// 未写完的行等待下次读取
+				partial = append(partial, line...)
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if len(partial) > 0 {
+				return handle(partial)
+			}
+			return nil
+		case <-time.After(followInterval):
+		}
+
+		if file == nil {
+			continue
+		}
+		current, err := os.Stat(name)
+		switch {
+		case os.IsNotExist(err):
+			// 轮转中 旧文件已重命名 新文件尚未创建
+		case err != nil:
+			return err
+		case !os.SameFile(info, current):
+			// 已轮转 读取旧文件剩余内容后切换到新文件
+			if err = drain(reader, partial, handle); err != nil {
+				return err
+			}
+			_ = file.Close()
+			file = nil
+		case current.Size() < offset:
+			// 文件被截断
+			if _, err = file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			reader, offset, partial = bufio.NewReader(file), 0, nil
+		}
+	}
+}
+
+// drain 读取剩余内容
+func drain(reader *bufio.Reader, partial []byte, handle func(line []byte) error) error {
+	rest, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	rest = append(partial, rest...)
+	for len(rest) > 0 {
+		idx := bytes.IndexByte(rest, '\n')
+		if idx < 0 {
+			return handle(rest)
+		}
+		if err = handle(rest[:idx+1]); err != nil {
+			return err
+		}
+		rest = rest[idx+1:]
+	}
+	return nil
+}
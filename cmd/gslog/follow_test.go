@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// startFollow 后台跟踪文件 读取的行写入返回的 channel
+func startFollow(t *testing.T, name string) <-chan string {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan string, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- follow(ctx, name, func(line []byte) error {
+			lines <- string(line)
+			return nil
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("follow: %v", err)
+		}
+	})
+	return lines
+}
+
+// expectLines 等待依次读取到 want
+func expectLines(t *testing.T, lines <-chan string, want ...string) {
+	t.Helper()
+
+	for _, line := range want {
+		select {
+		case got := <-lines:
+			if got != line {
+				t.Fatalf("line = %q, want %q", got, line)
+			}
+		case <-time.After(20 * followInterval):
+			t.Fatalf("timeout waiting for %q", line)
+		}
+	}
+}
+
+// appendFile 追加写入文件
+func appendFile(t *testing.T, name, data string) {
+	t.Helper()
+
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err = file.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFollowWaitsForFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	lines := startFollow(t, name)
+
+	time.Sleep(followInterval)
+	appendFile(t, name, "first\n")
+	expectLines(t, lines, "first\n")
+
+	// 未写完的行等待换行后再输出
+	appendFile(t, name, "par")
+	time.Sleep(2 * followInterval)
+	appendFile(t, name, "tial\n")
+	expectLines(t, lines, "partial\n")
+}
+
+func TestFollowRename(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, name, "old-1\n")
+	lines := startFollow(t, name)
+	expectLines(t, lines, "old-1\n")
+
+	// 与 LogFileRollover 相同 重命名后新建文件 重命名前写入的内容仍然输出
+	appendFile(t, name, "old-2\n")
+	if err := os.Rename(name, name+".1"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * followInterval)
+	appendFile(t, name, "new-1\n")
+	expectLines(t, lines, "old-2\n", "new-1\n")
+
+	appendFile(t, name, "new-2\n")
+	expectLines(t, lines, "new-2\n")
+}
+
+func TestFollowTruncate(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, name, "before-1\nbefore-2\n")
+	lines := startFollow(t, name)
+	expectLines(t, lines, "before-1\n", "before-2\n")
+
+	// 截断后从文件开头继续读取
+	if err := os.Truncate(name, 0); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, name, "after\n")
+	expectLines(t, lines, "after\n")
+
+	appendFile(t, name, "after-2\n")
+	expectLines(t, lines, "after-2\n")
+}
+
+func TestFollowFlushPartialOnCancel(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, name, "complete\nno newline")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []string
+	done := make(chan error, 1)
+	go func() {
+		done <- follow(ctx, name, func(line []byte) error {
+			got = append(got, string(line))
+			if len(got) == 1 {
+				cancel()
+			}
+			return nil
+		})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(20 * followInterval):
+		t.Fatal("timeout waiting for follow to stop")
+	}
+	if len(got) != 2 || got[0] != "complete\n" || got[1] != "no newline" {
+		t.Errorf("lines = %q, want complete line and partial line", got)
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gslog"
+)
+
+// formatter 日志输出格式
+type formatter interface {
+	format(buf *bytes.Buffer, entry *gslog.DecodedEntry)
+}
+
+// 输出格式
+const (
+	formatPretty = "pretty"
+	formatText   = "text"
+	formatJson   = "json"
+	formatLogfmt = "logfmt"
+)
+
+// newFormatter 根据名称创建输出格式
+func newFormatter(name string, color bool, layout string) (formatter, bool) {
+	switch name {
+	case formatPretty:
+		return &prettyFormatter{color: color, layout: layout}, true
+	case formatText:
+		return &textFormatter{layout: layout}, true
+	case formatJson:
+		return &jsonFormatter{layout: layout}, true
+	case formatLogfmt:
+		return &logfmtFormatter{layout: layout}, true
+	}
+	return nil, false
+}
+
+// ANSI 颜色
+const (
+	colorReset   = "\x1b[0m"
+	colorBold    = "\x1b[1m"
+	colorDim     = "\x1b[2m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+	colorGray    = "\x1b[90m"
+)
+
+// prettyFormatter 便于阅读的格式 时间 级别 消息 字段 (源码位置)
+type prettyFormatter struct {
+	color  bool
+	layout string
+}
+
+func (p *prettyFormatter) format(buf *bytes.Buffer, entry *gslog.DecodedEntry) {
+	if !entry.Time.IsZero() {
+		p.paint(buf, colorDim, entry.Time.Format(p.layout))
+		buf.WriteByte(' ')
+	}
+	level := entry.Level.UpCaseString()
+	if pad := 5 - utf8.RuneCountInString(level); pad > 0 {
+		level += strings.Repeat(" ", pad)
+	}
+	p.paint(buf, levelColor(entry.Level), level)
+	buf.WriteByte(' ')
+	if entry.Prefix != "" {
+		p.paint(buf, colorMagenta, "<"+entry.Prefix+">")
+		buf.WriteByte(' ')
+	}
+	p.paint(buf, colorBold, entry.Msg)
+
+	for _, field := range flattenFields("", entry.Fields) {
+		buf.WriteString("  ")
+		p.paint(buf, colorCyan, field.Key+"=")
+		buf.WriteString(field.Value.String())
+	}
+	if entry.File != "" {
+		buf.WriteString("  ")
+		source := entry.File + ":" + strconv.Itoa(entry.Line)
+		if entry.Function != "" {
+			source += " " + entry.Function
+		}
+		p.paint(buf, colorGray, "("+source+")")
+	}
+	buf.WriteByte('\n')
+}
+
+// paint 输出带颜色的文本
+func (p *prettyFormatter) paint(buf *bytes.Buffer, color, text string) {
+	if !p.color {
+		buf.WriteString(text)
+		return
+	}
+	buf.WriteString(color)
+	buf.WriteString(text)
+	buf.WriteString(colorReset)
+}
+
+// levelColor 日志级别颜色
+func levelColor(level gslog.LogLevel) string {
+	switch {
+	case level >= gslog.PanicLevel:
+		return colorMagenta + colorBold
+	case level >= gslog.ErrorLevel:
+		return colorRed
+	case level >= gslog.WarnLevel:
+		return colorYellow
+	case level >= gslog.InfoLevel:
+		return colorGreen
+	default:
+		return colorGray
+	}
+}
+
+// flattenFields 展开字段组 key 使用 a.b 形式
+func flattenFields(prefix string, fields []gslog.LogField) []gslog.LogField {
+	flattened := make([]gslog.LogField, 0, len(fields))
+	for _, field := range fields {
+		key := field.Key
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch field.Value.Kind() {
+		case gslog.LogFieldValueFields:
+			flattened = append(flattened, flattenFields(key, field.Value.Fields())...)
+		case gslog.LogFieldValueField:
+			flattened = append(flattened, flattenFields(key, []gslog.LogField{field.Value.Field()})...)
+		default:
+			flattened = append(flattened, gslog.LogField{Key: key, Value: field.Value})
+		}
+	}
+	return flattened
+}
+
+// textFormatter TextHandler 默认格式 time [Level] file:line function msg k=v
+type textFormatter struct {
+	layout string
+}
+
+func (t *textFormatter) format(buf *bytes.Buffer, entry *gslog.DecodedEntry) {
+	if entry.Prefix != "" {
+		buf.WriteString("<" + entry.Prefix + "> ")
+	}
+	if !entry.Time.IsZero() {
+		buf.WriteString(entry.Time.Format(t.layout))
+		buf.WriteByte(' ')
+	}
+	buf.WriteString("[" + entry.Level.CapitalString() + "] ")
+	if entry.File != "" {
+		buf.WriteString(entry.File + ":" + strconv.Itoa(entry.Line) + " ")
+	}
+	if entry.Function != "" {
+		buf.WriteString(entry.Function + " ")
+	}
+	buf.WriteString(entry.Msg)
+	buf.WriteByte(' ')
+	for _, field := range entry.Fields {
+		buf.WriteString(field.Key + "=" + field.Value.String() + " ")
+	}
+	buf.WriteByte('\n')
+}
+
+// jsonFormatter JsonHandler 默认格式 与 JsonHandler 共用编码器
+type jsonFormatter struct {
+	layout string
+}
+
+func (j *jsonFormatter) format(buf *bytes.Buffer, entry *gslog.DecodedEntry) {
+	buf.Write(entry.AppendJSON(buf.AvailableBuffer(), gslog.WithLayout(j.layout)))
+}
+
+// logfmtFormatter logfmt 格式 time=... level=... msg=... k=v 字段组展开为 a.b=v
+type logfmtFormatter struct {
+	layout string
+}
+
+func (l *logfmtFormatter) format(buf *bytes.Buffer, entry *gslog.DecodedEntry) {
+	if !entry.Time.IsZero() {
+		l.appendPair(buf, timeKey, entry.Time.Format(l.layout))
+	}
+	l.appendPair(buf, levelKey, entry.Level.LowCaseString())
+	if entry.File != "" {
+		l.appendPair(buf, "source", entry.File+":"+strconv.Itoa(entry.Line))
+	}
+	if entry.Function != "" {
+		l.appendPair(buf, functionKey, entry.Function)
+	}
+	l.appendPair(buf, msgKey, entry.Msg)
+	for _, field := range flattenFields("", entry.Fields) {
+		val := field.Value.String()
+		switch field.Value.Kind() {
+		case gslog.LogFieldValueTime:
+			val = field.Value.Time().Format(l.layout)
+		case gslog.LogFieldValueError:
+			val = field.Value.Error().Error()
+		}
+		l.appendPair(buf, field.Key, val)
+	}
+	buf.Truncate(buf.Len() - 1)
+	buf.WriteByte('\n')
+}
+
+// appendPair 输出 key=value<space> 包含空格 = 引号或为空时加引号
+func (l *logfmtFormatter) appendPair(buf *bytes.Buffer, key, val string) {
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	if val == "" || strings.ContainsAny(val, " =\"\t\r\n") || !utf8.ValidString(val) {
+		buf.WriteString(strconv.Quote(val))
+	} else {
+		buf.WriteString(val)
+	}
+	buf.WriteByte(' ')
+}
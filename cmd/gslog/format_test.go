@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"gslog"
+)
+
+// newFormatEntry 输出格式测试使用的日志
+func newFormatEntry() *gslog.DecodedEntry {
+	return &gslog.DecodedEntry{
+		Time:     time.Date(2024, 6, 11, 10, 0, 0, 0, time.Local),
+		Level:    gslog.WarnLevel,
+		Prefix:   "app",
+		File:     "main.go",
+		Line:     12,
+		Function: "main.run",
+		Msg:      "request done",
+		Fields: []gslog.LogField{
+			gslog.Int("status", 500),
+			gslog.String("path", "/api users"),
+			gslog.Fields("req", gslog.Int("id", 7), gslog.String("method", "GET")),
+		},
+	}
+}
+
+// formatEntry 使用指定格式输出
+func formatEntry(t *testing.T, name string, color bool, entry *gslog.DecodedEntry) string {
+	t.Helper()
+
+	f, ok := newFormatter(name, color, gslog.DefaultTimeLayout)
+	if !ok {
+		t.Fatalf("newFormatter(%q) not found", name)
+	}
+	var buf bytes.Buffer
+	f.format(&buf, entry)
+	return buf.String()
+}
+
+func TestFormatter(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{formatPretty, "2024/06/11 10:00:00.000000 WARN  <app> request done  status=500  path=/api users  req.id=7  req.method=GET  (main.go:12 main.run)\n"},
+		{formatText, "<app> 2024/06/11 10:00:00.000000 [Warn] main.go:12 main.run request done status=500 path=/api users req=[id=7, method=GET] \n"},
+		{formatJson, `{"time":"2024/06/11 10:00:00.000000","source":"main.go:12 main.run","level":"warn","message":"request done","fields":[{"status":500},{"path":"/api users"},{"req":[{"id":7},{"method":"GET"}]}]}` + "\n"},
+		{formatLogfmt, `time="2024/06/11 10:00:00.000000" level=warn source=main.go:12 function=main.run msg="request done" status=500 path="/api users" req.id=7 req.method=GET` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatEntry(t, tt.name, false, newFormatEntry()); got != tt.want {
+				t.Errorf("format =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := newFormatter("yaml", false, gslog.DefaultTimeLayout); ok {
+		t.Errorf("newFormatter(yaml) ok, want unknown format")
+	}
+}
+
+func TestFormatterPrettyColor(t *testing.T) {
+	got := formatEntry(t, formatPretty, true, newFormatEntry())
+	for _, want := range []string{
+		colorYellow + "WARN " + colorReset,
+		colorBold + "request done" + colorReset,
+		colorCyan + "status=" + colorReset + "500",
+		colorGray + "(main.go:12 main.run)" + colorReset,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("pretty = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestFormatterMinimalEntry(t *testing.T) {
+	// 没有时间以及源码位置的日志 例如未开启对应标记的文本日志
+	entry := &gslog.DecodedEntry{Level: gslog.InfoLevel, Msg: "hello"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{formatPretty, "INFO  hello\n"},
+		{formatText, "[Info] hello \n"},
+		{formatJson, `{"level":"info","message":"hello","fields":[]}` + "\n"},
+		{formatLogfmt, "level=info msg=hello\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatEntry(t, tt.name, false, entry); got != tt.want {
+				t.Errorf("format = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatterJsonMatchesHandler(t *testing.T) {
+	// JsonHandler 输出的日志解析后按 json 格式输出 应与原始日志一致
+	var buf bytes.Buffer
+	handler := gslog.NewJsonHandlerWithOptions(&bufferWriteSyncer{&buf})
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+	entry := gslog.NewLogEntry(time.Date(2024, 6, 11, 10, 0, 0, 0, time.Local), gslog.ErrorLevel, `quote " and <html> & 中文`, pcs[0])
+	entry.AppendFields(
+		gslog.Int("status", 500),
+		gslog.Float("ratio", 0.25),
+		gslog.String("escape", "tab\tline\nend\u2028"),
+		gslog.Bool("ok", false),
+		gslog.Any("nil", nil),
+		gslog.Slice("tags", []string{"a", "b"}),
+		gslog.Fields("req", gslog.Int("id", 7), gslog.Fields("inner", gslog.String("k", "v"))),
+	)
+	if err := handler.LogRecord(context.Background(), entry); err != nil {
+		t.Fatal(err)
+	}
+	line := buf.String()
+
+	decoded, err := gslog.NewJsonDecoder(nil).Decode([]byte(line))
+	if err != nil {
+		t.Fatalf("Decode(%q): %v", line, err)
+	}
+	if got := formatEntry(t, formatJson, false, decoded); got != line {
+		t.Errorf("json =\n%s\nwant\n%s", got, line)
+	}
+}
+
+// bufferWriteSyncer 写入内存的 gslog.WriteSyncer
+type bufferWriteSyncer struct {
+	*bytes.Buffer
+}
+
+func (b *bufferWriteSyncer) Sync() error {
+	return nil
+}
+
+func (b *bufferWriteSyncer) Close() error {
+	return nil
+}
@@ -0,0 +1,339 @@
+// gslog 查看 gslog 输出的文本以及Json日志
+//
+// 彩色输出文件或标准输入中的日志
+//
+//	gslog app.log
+//	tail -n 100 app.log | gslog
+//
+// 过滤日志级别 时间区间 消息以及字段
+//
+//	gslog -level warn -since 1h -grep timeout -where 'status>=500 and path~"/api"' app.log
+//
+// 转换格式 读取 LogFileRollover 的备份文件 以及持续跟踪轮转的日志文件
+//
+//	gslog -out logfmt -backups app.log
+//	gslog -f app.log
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"gslog"
+)
+
+// 输入格式
+const (
+	inputAuto = "auto"
+	inputText = "text"
+	inputJson = "json"
+)
+
+// 颜色模式
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// config 命令行参数
+type config struct {
+	input     string
+	output    string
+	color     string
+	layout    string
+	prefix    string
+	textFlags string
+	level     string
+	since     string
+	until     string
+	grep      string
+	where     string
+	follow    bool
+	backups   bool
+	raw       bool
+}
+
+// run 执行命令 返回退出码
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var conf config
+	flags := flag.NewFlagSet("gslog", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&conf.input, "in", inputAuto, "input format: auto, text or json")
+	flags.StringVar(&conf.output, "out", formatPretty, "output format: pretty, text, json or logfmt")
+	flags.StringVar(&conf.color, "color", colorAuto, "colorize pretty output: auto, always or never")
+	flags.StringVar(&conf.layout, "layout", gslog.DefaultTimeLayout, "time layout of input and output")
+	flags.StringVar(&conf.prefix, "prefix", "", "text log prefix")
+	flags.StringVar(&conf.textFlags, "text-flags", "time,file,level", "text log flags: time, file, function, level")
+	flags.StringVar(&conf.level, "level", "", "minimum level")
+	flags.StringVar(&conf.since, "since", "", "earliest time: RFC3339, layout, date or duration ago (1h)")
+	flags.StringVar(&conf.until, "until", "", "latest time: RFC3339, layout, date or duration ago (1h)")
+	flags.StringVar(&conf.grep, "grep", "", "message substring")
+	flags.StringVar(&conf.where, "where", "", `field expression, e.g. 'status>=500 and path~"/api"'`)
+	flags.BoolVar(&conf.follow, "f", false, "follow the file across rotations")
+	flags.BoolVar(&conf.backups, "backups", false, "also read rotated (and gzip) backups of each file")
+	flags.BoolVar(&conf.raw, "raw", false, "print lines that cannot be decoded even when filtering")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gslog [flags] [file ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	cmd, err := newCommand(&conf, stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, "gslog:", err)
+		return 2
+	}
+	if err = cmd.execute(flags.Args(), stdin); err != nil {
+		fmt.Fprintln(stderr, "gslog:", err)
+		return 1
+	}
+	return 0
+}
+
+// command 解析 过滤 输出
+type command struct {
+	conf      *config
+	filter    filter
+	formatter formatter
+	// 输入日志的配置 同时用于读取备份文件
+	decodeOptions []gslog.Options
+	text          gslog.Decoder
+	json          gslog.Decoder
+	out           *bufio.Writer
+	buf           bytes.Buffer
+}
+
+// newCommand 根据参数创建命令
+func newCommand(conf *config, stdout io.Writer) (*command, error) {
+	cmd := &command{conf: conf, out: bufio.NewWriter(stdout)}
+
+	switch conf.input {
+	case inputAuto, inputText, inputJson:
+	default:
+		return nil, fmt.Errorf("unknown input format %q", conf.input)
+	}
+	textFlag, err := parseTextFlags(conf.textFlags)
+	if err != nil {
+		return nil, err
+	}
+	cmd.decodeOptions = []gslog.Options{gslog.WithPrefix(conf.prefix), gslog.WithTextFlag(textFlag), gslog.WithLayout(conf.layout)}
+	cmd.text = gslog.NewTextDecoderWithOptions(cmd.decodeOptions...)
+	cmd.json = gslog.NewJsonDecoderWithOptions(cmd.decodeOptions...)
+
+	var color bool
+	switch conf.color {
+	case colorAuto:
+		color = isTerminal(stdout)
+	case colorAlways:
+		color = true
+	case colorNever:
+	default:
+		return nil, fmt.Errorf("unknown color mode %q", conf.color)
+	}
+	var ok bool
+	if cmd.formatter, ok = newFormatter(conf.output, color, conf.layout); !ok {
+		return nil, fmt.Errorf("unknown output format %q", conf.output)
+	}
+
+	if conf.level != "" {
+		level, err := gslog.ParseLogLevel(conf.level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid level %q", conf.level)
+		}
+		cmd.filter.level = &level
+	}
+	now := time.Now()
+	if conf.since != "" {
+		if cmd.filter.since, err = parseTime(conf.since, now); err != nil {
+			return nil, fmt.Errorf("invalid since %q", conf.since)
+		}
+	}
+	if conf.until != "" {
+		if cmd.filter.until, err = parseTime(conf.until, now); err != nil {
+			return nil, fmt.Errorf("invalid until %q", conf.until)
+		}
+	}
+	cmd.filter.contains = conf.grep
+	if conf.where != "" {
+		if cmd.filter.where, err = parseExpr(conf.where); err != nil {
+			return nil, fmt.Errorf("invalid where expression: %w", err)
+		}
+	}
+	return cmd, nil
+}
+
+// execute 依次处理输入
+func (c *command) execute(files []string, stdin io.Reader) error {
+	defer c.out.Flush()
+
+	if c.conf.follow {
+		if len(files) != 1 || files[0] == "-" {
+			return errors.New("-f requires exactly one file")
+		}
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		return follow(ctx, files[0], func(line []byte) error {
+			if err := c.handle(line); err != nil {
+				return err
+			}
+			return c.out.Flush()
+		})
+	}
+
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, name := range files {
+		if err := c.executeFile(name, stdin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeFile 处理单个输入 - 表示标准输入
+func (c *command) executeFile(name string, stdin io.Reader) error {
+	var reader io.Reader
+	switch {
+	case name == "-":
+		reader = stdin
+	case c.conf.backups:
+		rollover := gslog.NewLogFileRollover(name, 0, 0, 0, false)
+		readCloser, err := rollover.OpenReader(c.filter.since, c.filter.until, c.decodeOptions...)
+		if err != nil {
+			return err
+		}
+		defer readCloser.Close()
+		reader = readCloser
+	default:
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	lines := bufio.NewReader(reader)
+	for {
+		line, err := lines.ReadBytes('\n')
+		if len(line) > 0 {
+			if errHandle := c.handle(line); errHandle != nil {
+				return errHandle
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handle 处理一行日志 无法解析的行原样输出 设置了过滤条件时丢弃
+func (c *command) handle(line []byte) error {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 {
+		return nil
+	}
+	entry, err := c.decode(trimmed)
+	if err != nil {
+		if c.filter.active() && !c.conf.raw {
+			return nil
+		}
+		_, err = c.out.Write(append(trimmed, '\n'))
+		return err
+	}
+	if !c.filter.match(entry) {
+		return nil
+	}
+
+	c.buf.Reset()
+	c.formatter.format(&c.buf, entry)
+	_, err = c.out.Write(c.buf.Bytes())
+	return err
+}
+
+// decode 按输入格式解析 auto 时以 { 开头的行按Json解析
+func (c *command) decode(line []byte) (*gslog.DecodedEntry, error) {
+	switch c.conf.input {
+	case inputText:
+		return c.text.Decode(line)
+	case inputJson:
+		return c.json.Decode(line)
+	}
+	if line[0] == '{' {
+		if entry, err := c.json.Decode(line); err == nil {
+			return entry, nil
+		}
+	}
+	return c.text.Decode(line)
+}
+
+// parseTextFlags 解析文本日志标记位
+func parseTextFlags(text string) (gslog.LTextFlag, error) {
+	var textFlag gslog.LTextFlag
+	for _, name := range strings.Split(text, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "time":
+			textFlag |= gslog.LTextTime
+		case "file":
+			textFlag |= gslog.LTextFile
+		case "function":
+			textFlag |= gslog.LTextFunction
+		case "level":
+			textFlag |= gslog.LTextLogLevel
+		default:
+			return 0, fmt.Errorf("unknown text flag %q", name)
+		}
+	}
+	return textFlag, nil
+}
+
+// parseTime 解析时间参数 支持 RFC3339 日志默认格式 日期以及相对当前时间的时长(1h 表示一小时前)
+func parseTime(text string, now time.Time) (time.Time, error) {
+	if !now.IsZero() {
+		if duration, err := time.ParseDuration(text); err == nil {
+			return now.Add(-duration), nil
+		}
+	}
+	var err error
+	for _, layout := range []string{time.RFC3339Nano, gslog.DefaultTimeLayout, time.DateTime, time.DateOnly} {
+		var tm time.Time
+		if tm, err = time.ParseInLocation(layout, text, time.Local); err == nil {
+			return tm, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// isTerminal 输出是否为终端
+func isTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -1,8 +1,11 @@
 package gslog
 
 const (
-	badFieldsKey = "!badFieldsKey"
-	unknownFile  = "!unknownFile"
+	unknownFile = "!unknownFile"
+	// Err 默认key
+	errorFieldKey = "error"
+	// 日志发生截断时追加的字段key
+	truncatedFieldKey = "_truncated"
 )
 
 type LTextFlag int
@@ -46,4 +49,5 @@ const (
 	serializeJsonStart       = '{'
 	serializeJsonEnd         = '}'
 	serializeStringMarks     = '"'
+	serializeListSplit       = ", "
 )
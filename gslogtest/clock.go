@@ -0,0 +1,85 @@
+package gslogtest
+
+import (
+	"sync"
+	"time"
+
+	"gslog"
+)
+
+var (
+	// 检查 FakeClock 实现 Clock
+	_ gslog.Clock = (*FakeClock)(nil)
+)
+
+var (
+	// 确定性时钟默认起始时间
+	defaultClockStart = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+const (
+	// 确定性时钟默认步长
+	defaultClockStep = time.Millisecond
+)
+
+// DeterministicClock 确定性时钟 从 2024-01-01T00:00:00Z 开始 每次调用递增 1ms
+func DeterministicClock() *FakeClock {
+	return SteppingClock(defaultClockStart, defaultClockStep)
+}
+
+// SteppingClock 从 start 开始 每次调用递增 step 的时钟 并发安全
+func SteppingClock(start time.Time, step time.Duration) *FakeClock {
+	clock := NewFakeClock(start)
+	clock.SetStep(step)
+	return clock
+}
+
+// FakeClock 手动控制的时钟 可注入 Logger.WithClock 以及 LogFileRollover.Clock
+// 用于固定日志时间 备份文件命名以及驱动 MaxAge 过期 并发安全
+type FakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+	step  time.Duration
+}
+
+// NewFakeClock 创建从 start 开始的时钟 start 为零值时从 2024-01-01T00:00:00Z 开始
+func NewFakeClock(start time.Time) *FakeClock {
+	if start.IsZero() {
+		start = defaultClockStart
+	}
+	return &FakeClock{now: start}
+}
+
+// Now 实现 gslog.Clock 设置了步长时每次调用后自动前进
+func (f *FakeClock) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	current := f.now
+	f.now = f.now.Add(f.step)
+	return current
+}
+
+// Set 设置当前时间
+func (f *FakeClock) Set(now time.Time) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.now = now
+}
+
+// Advance 时间前进 d
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.now = f.now.Add(d)
+}
+
+// SetStep 设置每次调用 Now 后自动前进的步长 0 不自动前进
+func (f *FakeClock) SetStep(step time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.step = step
+}
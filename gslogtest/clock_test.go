@@ -0,0 +1,135 @@
+package gslogtest
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gslog"
+)
+
+// bufferWriter 测试用内存 gslog.WriteSyncer
+type bufferWriter struct {
+	strings.Builder
+}
+
+func (b *bufferWriter) Sync() error {
+	return nil
+}
+
+func (b *bufferWriter) Close() error {
+	return nil
+}
+
+// listDir 目录下的文件名
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestFakeClockLoggerTime(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 6, 11, 10, 0, 0, 0, time.Local))
+	writer := &bufferWriter{}
+	logger := gslog.NewLogger(gslog.NewTextHandlerWithOptions(writer, gslog.WithTextFlag(gslog.LTextTime))).WithClock(clock)
+
+	logger.Info("first")
+	clock.Advance(1500 * time.Millisecond)
+	logger.Info("second")
+	clock.Set(time.Date(2025, 1, 2, 3, 4, 5, 6000, time.Local))
+	logger.Info("third")
+
+	want := "2024/06/11 10:00:00.000000 first \n" +
+		"2024/06/11 10:00:01.500000 second \n" +
+		"2025/01/02 03:04:05.000006 third \n"
+	if got := writer.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFakeClockStep(t *testing.T) {
+	start := time.Date(2024, 6, 11, 10, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	clock.SetStep(time.Second)
+	for idx := 0; idx < 3; idx++ {
+		if got, want := clock.Now(), start.Add(time.Duration(idx)*time.Second); !got.Equal(want) {
+			t.Errorf("Now() #%d = %v, want %v", idx, got, want)
+		}
+	}
+	if got := NewFakeClock(time.Time{}).Now(); !got.Equal(defaultClockStart) {
+		t.Errorf("zero start Now() = %v, want %v", got, defaultClockStart)
+	}
+}
+
+func TestFakeClockRolloverBackupName(t *testing.T) {
+	dir := t.TempDir()
+	rollover := gslog.NewLogFileRollover(filepath.Join(dir, "app.log"), 1, 0, 0, false)
+	rollover.Clock = NewFakeClock(time.Date(2024, 6, 11, 10, 20, 30, 456000000, time.Local))
+	t.Cleanup(func() { _ = rollover.Close() })
+
+	if _, err := rollover.Write([]byte("line\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := rollover.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"app.log", "app_2024-06-11T10-20-30.456.log"}
+	if got := listDir(t, dir); !slices.Equal(got, want) {
+		t.Errorf("files = %q, want %q", got, want)
+	}
+}
+
+func TestFakeClockRolloverMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	clock := NewFakeClock(time.Date(2024, 6, 11, 0, 0, 0, 0, time.Local))
+	rollover := gslog.NewLogFileRollover(filepath.Join(dir, "app.log"), 1, 0, 1, false)
+	rollover.Clock = clock
+	t.Cleanup(func() { _ = rollover.Close() })
+
+	rotate := func() {
+		t.Helper()
+		if _, err := rollover.Write([]byte("line\n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := rollover.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 等待后台协程完成清理
+	waitFiles := func(want ...string) {
+		t.Helper()
+		var got []string
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if got = listDir(t, dir); slices.Equal(got, want) {
+				return
+			}
+		}
+		t.Fatalf("files = %q, want %q", got, want)
+	}
+
+	rotate()
+	clock.Advance(12 * time.Hour)
+	rotate()
+	// 都在一天之内 全部保留
+	waitFiles("app.log", "app_2024-06-11T00-00-00.000.log", "app_2024-06-11T12-00-00.000.log")
+
+	// 前进到第一个备份过期 第二个备份仍在一天之内
+	clock.Advance(13 * time.Hour)
+	rotate()
+	waitFiles("app.log", "app_2024-06-11T12-00-00.000.log", "app_2024-06-12T01-00-00.000.log")
+
+	clock.Advance(48 * time.Hour)
+	rotate()
+	waitFiles("app.log", "app_2024-06-14T01-00-00.000.log")
+}
@@ -0,0 +1,636 @@
+package gslogtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gslog"
+)
+
+var (
+	// 检查 memoryWriter 实现 WriteSyncer
+	_ gslog.WriteSyncer = (*memoryWriter)(nil)
+)
+
+// 解析结果中的标准key LineParser 需要将日志时间/级别/消息映射到这些key
+const (
+	TimeKey    = "time"
+	LevelKey   = "level"
+	MessageKey = "msg"
+)
+
+const (
+	// 并发测试 goroutine 数量以及每个 goroutine 输出的日志数
+	concurrencyGoroutines = 16
+	concurrencyEntries    = 64
+)
+
+// HandlerFactory 创建待测 LogHandler
+type HandlerFactory func(writeSyncer gslog.WriteSyncer, opts ...gslog.Options) gslog.LogHandler
+
+// LineParser 解析一行日志输出
+// 时间/级别/消息分别放入 TimeKey/LevelKey/MessageKey 时间为零值时不应存在 TimeKey
+// 字段放在顶层 Fields 字段组解析为 map[string]any
+type LineParser func(line []byte) (map[string]any, error)
+
+// TestHandler 验证自定义 LogHandler 的行为与 TextHandler/JsonHandler 一致
+// 覆盖级别过滤 零值时间 Fields 字段组 错误 nil 值 并发安全 Sync/Close 幂等 With 字段
+// 以及 Handler 自身的字段组与预编码静态字段
+// 并发用例需要配合 go test -race 检查数据竞争
+func TestHandler(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	t.Helper()
+
+	cases := []struct {
+		name string
+		run  func(t *testing.T, newHandler HandlerFactory, parse LineParser)
+	}{
+		{"LevelFiltering", testLevelFiltering},
+		{"ZeroTime", testZeroTime},
+		{"Message", testMessage},
+		{"Fields", testFields},
+		{"NestedFields", testNestedFields},
+		{"Error", testError},
+		{"NilValue", testNilValue},
+		{"With", testWith},
+		{"HandlerGroup", testHandlerGroup},
+		{"StaticFields", testStaticFields},
+		{"Concurrency", testConcurrency},
+		{"SyncClose", testSyncClose},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.run(t, newHandler, parse)
+		})
+	}
+}
+
+// testLevelFiltering 低于配置级别的日志不输出
+func testLevelFiltering(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	writer := &memoryWriter{}
+	handler := newHandler(writer, gslog.WithLevel(gslog.WarnLevel))
+	ctx := context.Background()
+
+	if handler.Enabled(ctx, gslog.InfoLevel) {
+		t.Errorf("Enabled(InfoLevel) = true with WarnLevel configured")
+	}
+	if !handler.Enabled(ctx, gslog.ErrorLevel) {
+		t.Errorf("Enabled(ErrorLevel) = false with WarnLevel configured")
+	}
+
+	logger := gslog.NewLogger(handler)
+	logger.Info("filtered")
+	logger.Warn("kept")
+	records := parseLines(t, writer, parse)
+	if len(records) != 1 {
+		t.Fatalf("got %d lines, want 1", len(records))
+	}
+	checkValue(t, records[0], MessageKey, "kept")
+	checkLevel(t, records[0], gslog.WarnLevel)
+}
+
+// testZeroTime 零值时间不输出
+func testZeroTime(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	writer := &memoryWriter{}
+	handler := newHandler(writer, gslog.WithLevel(gslog.TraceLevel))
+
+	entry := gslog.NewLogEntry(time.Time{}, gslog.InfoLevel, "zero time", 0)
+	if err := handler.LogRecord(context.Background(), entry); err != nil {
+		t.Fatalf("LogRecord: %v", err)
+	}
+	entry = gslog.NewLogEntry(time.Now(), gslog.InfoLevel, "with time", 0)
+	if err := handler.LogRecord(context.Background(), entry); err != nil {
+		t.Fatalf("LogRecord: %v", err)
+	}
+
+	records := parseLines(t, writer, parse)
+	if len(records) != 2 {
+		t.Fatalf("got %d lines, want 2", len(records))
+	}
+	if _, ok := records[0][TimeKey]; ok {
+		t.Errorf("zero time: unexpected %q in %v", TimeKey, records[0])
+	}
+	if _, ok := records[1][TimeKey]; !ok {
+		t.Errorf("non-zero time: missing %q in %v", TimeKey, records[1])
+	}
+}
+
+// testMessage 消息以及级别
+func testMessage(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	writer := &memoryWriter{}
+	logger := gslog.NewLogger(newHandler(writer, gslog.WithLevel(gslog.TraceLevel)))
+	logger.Debug("debug message")
+	logger.Error("error message")
+
+	records := parseLines(t, writer, parse)
+	if len(records) != 2 {
+		t.Fatalf("got %d lines, want 2", len(records))
+	}
+	checkValue(t, records[0], MessageKey, "debug message")
+	checkLevel(t, records[0], gslog.DebugLevel)
+	checkValue(t, records[1], MessageKey, "error message")
+	checkLevel(t, records[1], gslog.ErrorLevel)
+}
+
+// testFields 基础类型字段
+func testFields(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	writer := &memoryWriter{}
+	logger := gslog.NewLogger(newHandler(writer, gslog.WithLevel(gslog.TraceLevel)))
+	logger.Info("fields", "string", "value", "int", 42, gslog.Bool("bool", true), gslog.Float("float", 1.5))
+
+	records := parseLines(t, writer, parse)
+	if len(records) != 1 {
+		t.Fatalf("got %d lines, want 1", len(records))
+	}
+	checkValue(t, records[0], "string", "value")
+	checkValue(t, records[0], "int", 42)
+	checkValue(t, records[0], "bool", true)
+	checkValue(t, records[0], "float", 1.5)
+}
+
+// testNestedFields Fields 字段组
+func testNestedFields(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	writer := &memoryWriter{}
+	logger := gslog.NewLogger(newHandler(writer, gslog.WithLevel(gslog.TraceLevel)))
+	logger.Info("nested", gslog.Fields("request",
+		gslog.String("method", "GET"),
+		gslog.Int("status", 200),
+		gslog.Fields("user", gslog.String("id", "u1"), gslog.String("role", "admin")),
+	))
+
+	records := parseLines(t, writer, parse)
+	if len(records) != 1 {
+		t.Fatalf("got %d lines, want 1", len(records))
+	}
+	request, ok := records[0]["request"].(map[string]any)
+	if !ok {
+		t.Fatalf("request = %#v, want map[string]any", records[0]["request"])
+	}
+	checkValue(t, request, "method", "GET")
+	checkValue(t, request, "status", 200)
+	user, ok := request["user"].(map[string]any)
+	if !ok {
+		t.Fatalf("request.user = %#v, want map[string]any", request["user"])
+	}
+	checkValue(t, user, "id", "u1")
+	checkValue(t, user, "role", "admin")
+}
+
+// testError 错误字段包含错误信息
+func testError(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	writer := &memoryWriter{}
+	logger := gslog.NewLogger(newHandler(writer, gslog.WithLevel(gslog.TraceLevel)))
+	logger.Info("error", gslog.Err(fmt.Errorf("wrapped: %w", errors.New("boom"))))
+
+	records := parseLines(t, writer, parse)
+	if len(records) != 1 {
+		t.Fatalf("got %d lines, want 1", len(records))
+	}
+	got, ok := records[0]["error"]
+	if !ok {
+		t.Fatalf("missing error field in %v", records[0])
+	}
+	if text := fmt.Sprint(got); !strings.Contains(text, "boom") {
+		t.Errorf("error = %s, want containing %q", text, "boom")
+	}
+}
+
+// testNilValue nil 值字段不会导致输出失败
+func testNilValue(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	writer := &memoryWriter{}
+	logger := gslog.NewLogger(newHandler(writer, gslog.WithLevel(gslog.TraceLevel)))
+	var nilPtr *int
+	logger.Info("nil", "any", nil, "ptr", nilPtr, gslog.Err(nil))
+
+	records := parseLines(t, writer, parse)
+	if len(records) != 1 {
+		t.Fatalf("got %d lines, want 1", len(records))
+	}
+	for _, key := range []string{"any", "ptr", "error"} {
+		if _, ok := records[0][key]; !ok {
+			t.Errorf("missing %q in %v", key, records[0])
+		}
+	}
+}
+
+// testWith Logger.With 字段在调用字段之前输出 字段组保持嵌套
+func testWith(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	writer := &memoryWriter{}
+	logger := gslog.NewLogger(newHandler(writer, gslog.WithLevel(gslog.TraceLevel)))
+	child := logger.With(gslog.String("service", "api"), gslog.Fields("request", gslog.String("id", "r1")))
+	child.Info("with", "status", 200)
+	logger.Info("parent")
+
+	records := parseLines(t, writer, parse)
+	if len(records) != 2 {
+		t.Fatalf("got %d lines, want 2", len(records))
+	}
+	checkValue(t, records[0], "service", "api")
+	checkValue(t, records[0], "status", 200)
+	request, ok := records[0]["request"].(map[string]any)
+	if !ok {
+		t.Fatalf("request = %#v, want map[string]any", records[0]["request"])
+	}
+	checkValue(t, request, "id", "r1")
+	// With 不影响原日志器
+	if _, ok := records[1]["service"]; ok {
+		t.Errorf("parent logger has With field: %v", records[1])
+	}
+}
+
+// testHandlerGroup 直接交给 Handler 的字段组 包括单个字段的字段组以及多层嵌套
+func testHandlerGroup(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	writer := &memoryWriter{}
+	handler := newHandler(writer, gslog.WithLevel(gslog.TraceLevel))
+
+	entry := gslog.NewLogEntry(time.Now(), gslog.InfoLevel, "group", 0)
+	entry.AppendFields(
+		gslog.Fields("single", gslog.String("id", "s1")),
+		gslog.Fields("outer", gslog.Int("count", 2), gslog.Fields("inner", gslog.String("id", "i1"))),
+		gslog.String("after", "v"),
+	)
+	if err := handler.LogRecord(context.Background(), entry); err != nil {
+		t.Fatalf("LogRecord: %v", err)
+	}
+
+	records := parseLines(t, writer, parse)
+	if len(records) != 1 {
+		t.Fatalf("got %d lines, want 1", len(records))
+	}
+	single, ok := records[0]["single"].(map[string]any)
+	if !ok {
+		t.Fatalf("single = %#v, want map[string]any", records[0]["single"])
+	}
+	checkValue(t, single, "id", "s1")
+	outer, ok := records[0]["outer"].(map[string]any)
+	if !ok {
+		t.Fatalf("outer = %#v, want map[string]any", records[0]["outer"])
+	}
+	checkValue(t, outer, "count", 2)
+	inner, ok := outer["inner"].(map[string]any)
+	if !ok {
+		t.Fatalf("outer.inner = %#v, want map[string]any", outer["inner"])
+	}
+	checkValue(t, inner, "id", "i1")
+	// 字段组之后的字段不属于字段组
+	checkValue(t, records[0], "after", "v")
+}
+
+// testStaticFields Handler 预编码的静态字段 包括字段组 WithOptions 追加静态字段后重新编码
+func testStaticFields(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	writer := &memoryWriter{}
+	handler := newHandler(writer, gslog.WithLevel(gslog.TraceLevel), gslog.WithStaticFields(
+		gslog.String("service", "api"),
+		gslog.Fields("meta", gslog.String("region", "eu"), gslog.Int("zone", 2)),
+	))
+	logger := gslog.NewLogger(handler)
+	logger.Info("static", "status", 200)
+	logger.Info("static again")
+
+	records := parseLines(t, writer, parse)
+	if len(records) != 2 {
+		t.Fatalf("got %d lines, want 2", len(records))
+	}
+	for _, record := range records {
+		checkValue(t, record, "service", "api")
+		meta, ok := record["meta"].(map[string]any)
+		if !ok {
+			t.Fatalf("meta = %#v, want map[string]any", record["meta"])
+		}
+		checkValue(t, meta, "region", "eu")
+		checkValue(t, meta, "zone", 2)
+	}
+	checkValue(t, records[0], "status", 200)
+
+	// 支持 WithOptions 的 Handler 修改静态字段后立即生效
+	configurable, ok := handler.(interface{ WithOptions(opts ...gslog.Options) })
+	if !ok {
+		return
+	}
+	configurable.WithOptions(gslog.WithStaticFields(gslog.String("stage", "beta")))
+	logger.Info("reconfigured")
+
+	records = parseLines(t, writer, parse)
+	if len(records) != 3 {
+		t.Fatalf("got %d lines, want 3", len(records))
+	}
+	checkValue(t, records[2], "service", "api")
+	checkValue(t, records[2], "stage", "beta")
+	if _, ok := records[2]["meta"].(map[string]any); !ok {
+		t.Errorf("meta = %#v, want map[string]any", records[2]["meta"])
+	}
+}
+
+// testConcurrency 并发写入每行日志完整
+func testConcurrency(t *testing.T, newHandler HandlerFactory, parse LineParser) {
+	writer := &memoryWriter{}
+	logger := gslog.NewLogger(newHandler(writer, gslog.WithLevel(gslog.TraceLevel)))
+
+	var wg sync.WaitGroup
+	for routine := 0; routine < concurrencyGoroutines; routine++ {
+		wg.Add(1)
+		go func(routine int) {
+			defer wg.Done()
+			child := logger.With(gslog.Int("goroutine", routine))
+			for idx := 0; idx < concurrencyEntries; idx++ {
+				child.Info("concurrent", "index", idx)
+			}
+		}(routine)
+	}
+	wg.Wait()
+
+	records := parseLines(t, writer, parse)
+	if want := concurrencyGoroutines * concurrencyEntries; len(records) != want {
+		t.Fatalf("got %d lines, want %d", len(records), want)
+	}
+	seen := make(map[string]bool, len(records))
+	for _, record := range records {
+		key := fmt.Sprint(record["goroutine"], "/", record["index"])
+		if seen[key] {
+			t.Fatalf("duplicate entry %s", key)
+		}
+		seen[key] = true
+	}
+}
+
+// testSyncClose Sync/Close 可以重复调用 writeSyncer 最多关闭一次 关闭后不再 Sync
+func testSyncClose(t *testing.T, newHandler HandlerFactory, _ LineParser) {
+	writer := &memoryWriter{}
+	handler := newHandler(writer, gslog.WithLevel(gslog.TraceLevel))
+	gslog.NewLogger(handler).Info("before close")
+
+	for idx := 0; idx < 2; idx++ {
+		if err := handler.Sync(); err != nil {
+			t.Errorf("Sync #%d: %v", idx+1, err)
+		}
+	}
+	for idx := 0; idx < 2; idx++ {
+		if err := handler.Close(); err != nil {
+			t.Errorf("Close #%d: %v", idx+1, err)
+		}
+	}
+	if err := handler.Sync(); err != nil {
+		t.Errorf("Sync after Close: %v", err)
+	}
+
+	syncs, closes, syncsAfterClose := writer.counts()
+	if closes > 1 {
+		t.Errorf("writer closed %d times, want at most 1", closes)
+	}
+	if syncsAfterClose > 0 {
+		t.Errorf("writer synced %d times after Close (%d syncs total)", syncsAfterClose, syncs)
+	}
+}
+
+// parseLines 解析全部输出
+func parseLines(t *testing.T, writer *memoryWriter, parse LineParser) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for _, line := range writer.lines() {
+		record, err := parse(line)
+		if err != nil {
+			t.Fatalf("parse %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// checkValue 按 fmt.Sprint 比较字段值 兼容文本格式的字符串值以及Json的 float64
+func checkValue(t *testing.T, record map[string]any, key string, want any) {
+	t.Helper()
+
+	got, ok := record[key]
+	if !ok {
+		t.Errorf("missing %q in %v", key, record)
+		return
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("%s = %v, want %v", key, got, want)
+	}
+}
+
+// checkLevel 日志级别 支持名称(任意大小写)或数值
+func checkLevel(t *testing.T, record map[string]any, want gslog.LogLevel) {
+	t.Helper()
+
+	got, ok := record[LevelKey]
+	if !ok {
+		t.Errorf("missing %q in %v", LevelKey, record)
+		return
+	}
+	var level gslog.LogLevel
+	if err := level.UnmarshalText([]byte(fmt.Sprint(got))); err != nil || level != want {
+		t.Errorf("%s = %v, want %s", LevelKey, got, want)
+	}
+}
+
+// ParseJSONLine 解析 JsonHandler 默认key的输出 作为 TestHandler 的 LineParser
+func ParseJSONLine(line []byte) (map[string]any, error) {
+	var raw map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	record := make(map[string]any, len(raw))
+	for key, val := range raw {
+		switch key {
+		case "message":
+			record[MessageKey] = val
+		case "fields":
+			fields, ok := val.([]any)
+			if !ok {
+				return nil, fmt.Errorf("fields is %T, want array", val)
+			}
+			flattenJSONFields(record, fields)
+		default:
+			// 静态字段位于顶层
+			flattenJSONFields(record, []any{map[string]any{key: val}})
+		}
+	}
+	return record, nil
+}
+
+// flattenJSONFields 将 [{"k":v},{"k2":v2}] 展开到 record Fields 字段组转换为 map
+func flattenJSONFields(record map[string]any, fields []any) {
+	for _, field := range fields {
+		object, ok := field.(map[string]any)
+		if !ok {
+			continue
+		}
+		for key, val := range object {
+			if group, isGroup := jsonFieldGroup(val); isGroup {
+				nested := make(map[string]any, len(group))
+				flattenJSONFields(nested, group)
+				record[key] = nested
+				continue
+			}
+			record[key] = val
+		}
+	}
+}
+
+// jsonFieldGroup 判断是否为 Fields 字段组 即元素均为单个key对象的数组
+func jsonFieldGroup(val any) ([]any, bool) {
+	group, ok := val.([]any)
+	if !ok || len(group) == 0 {
+		return nil, false
+	}
+	for _, elem := range group {
+		if object, isObject := elem.(map[string]any); !isObject || len(object) != 1 {
+			return nil, false
+		}
+	}
+	return group, true
+}
+
+// TextLineParser 使用 TextDecoder 解析 TextHandler 输出 作为 TestHandler 的 LineParser
+// opts 需要与创建 TextHandler 时的 TextFlag/前缀/时间格式一致
+// 字段组解析为 map[string]any 单个字段的字段组 a.b=v 同样展开为嵌套 map
+func TextLineParser(opts ...gslog.Options) LineParser {
+	decoder := gslog.NewTextDecoderWithOptions(opts...)
+	return func(line []byte) (map[string]any, error) {
+		entry, err := decoder.Decode(line)
+		if err != nil {
+			return nil, err
+		}
+		record := make(map[string]any, len(entry.Fields)+3)
+		if !entry.Time.IsZero() {
+			record[TimeKey] = entry.Time
+		}
+		record[LevelKey] = entry.Level.CapitalString()
+		record[MessageKey] = entry.Msg
+		for _, field := range entry.Fields {
+			setDottedKey(record, field.Key, decodedValue(field.Value))
+		}
+		return record, nil
+	}
+}
+
+// setDottedKey 将 a.b 形式的 key 写入嵌套 map 中间节点已存在且不是 map 时保留原始 key
+func setDottedKey(record map[string]any, key string, val any) {
+	parts := strings.Split(key, ".")
+	current := record
+	for _, part := range parts[:len(parts)-1] {
+		next, exists := current[part]
+		if !exists {
+			nested := make(map[string]any)
+			current[part] = nested
+			current = nested
+			continue
+		}
+		nested, ok := next.(map[string]any)
+		if !ok {
+			record[key] = val
+			return
+		}
+		current = nested
+	}
+	current[parts[len(parts)-1]] = val
+}
+
+// decodedValue 解析后的字段值转换为 Go 值 字段组转换为 map[string]any
+func decodedValue(val gslog.LogFieldValue) any {
+	switch val.Kind() {
+	case gslog.LogFieldValueFields:
+		record := make(map[string]any, len(val.Fields()))
+		for _, field := range val.Fields() {
+			setDottedKey(record, field.Key, decodedValue(field.Value))
+		}
+		return record
+	case gslog.LogFieldValueField:
+		field := val.Field()
+		record := make(map[string]any, 1)
+		setDottedKey(record, field.Key, decodedValue(field.Value))
+		return record
+	case gslog.LogFieldValueMap:
+		record := make(map[string]any, len(val.Map()))
+		for _, field := range val.Map() {
+			record[field.Key] = decodedValue(field.Value)
+		}
+		return record
+	case gslog.LogFieldValueSlice:
+		values := make([]any, 0, len(val.Slice()))
+		for _, elem := range val.Slice() {
+			values = append(values, decodedValue(elem))
+		}
+		return values
+	case gslog.LogFieldValueError:
+		if err := val.Error(); err != nil {
+			return err.Error()
+		}
+		return nil
+	default:
+		return val.Any()
+	}
+}
+
+// memoryWriter 内存 WriteSyncer
+type memoryWriter struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+	// Sync/Close 调用次数 以及关闭后的 Sync 调用次数
+	syncs           int
+	closes          int
+	syncsAfterClose int
+}
+
+// Write 实现 io.Writer
+func (m *memoryWriter) Write(data []byte) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.buffer.Write(data)
+}
+
+// Sync 实现 WriteSyncer
+func (m *memoryWriter) Sync() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.syncs++
+	if m.closes > 0 {
+		m.syncsAfterClose++
+	}
+	return nil
+}
+
+// Close 实现 WriteSyncer
+func (m *memoryWriter) Close() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.closes++
+	return nil
+}
+
+// counts Sync/Close 调用次数 以及关闭后的 Sync 调用次数
+func (m *memoryWriter) counts() (syncs, closes, syncsAfterClose int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.syncs, m.closes, m.syncsAfterClose
+}
+
+// lines 按行拆分输出
+func (m *memoryWriter) lines() [][]byte {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var lines [][]byte
+	for _, line := range bytes.Split(m.buffer.Bytes(), []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			lines = append(lines, bytes.Clone(line))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,20 @@
+package gslogtest
+
+import (
+	"testing"
+
+	"gslog"
+)
+
+func TestTextHandlerConformance(t *testing.T) {
+	TestHandler(t, func(writeSyncer gslog.WriteSyncer, opts ...gslog.Options) gslog.LogHandler {
+		opts = append([]gslog.Options{gslog.WithTextFlag(gslog.DefaultLTextFlag)}, opts...)
+		return gslog.NewTextHandlerWithOptions(writeSyncer, opts...)
+	}, TextLineParser(gslog.WithTextFlag(gslog.DefaultLTextFlag)))
+}
+
+func TestJsonHandlerConformance(t *testing.T) {
+	TestHandler(t, func(writeSyncer gslog.WriteSyncer, opts ...gslog.Options) gslog.LogHandler {
+		return gslog.NewJsonHandlerWithOptions(writeSyncer, opts...)
+	}, ParseJSONLine)
+}
@@ -0,0 +1,104 @@
+package gslogtest
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"gslog"
+)
+
+var (
+	// 检查 testWriter 实现 WriteSyncer
+	_ gslog.WriteSyncer = (*testWriter)(nil)
+	// 检查 testHandler 实现 LogHandler
+	_ gslog.LogHandler = (*testHandler)(nil)
+)
+
+// NewTestLogger 创建输出到 t.Log 的日志器 默认输出全部级别
+// 日志时间使用 DeterministicClock 测试结束后的日志会被丢弃
+// t.Helper 无法标记 gslog 内部的帧 t.Log 报告的位置位于 gslog 内部
+// 因此每行日志以调用处的 file:line: 开头 与 t.Log 的格式一致
+func NewTestLogger(t testing.TB, opts ...gslog.Options) *gslog.Logger {
+	t.Helper()
+
+	writer := &testWriter{t: t}
+	t.Cleanup(writer.done)
+
+	options := append([]gslog.Options{
+		gslog.WithLevel(gslog.TraceLevel),
+		gslog.WithTextFlag(gslog.LTextTime | gslog.LTextLogLevel),
+	}, opts...)
+	handler := &testHandler{
+		TextHandler: gslog.NewTextHandlerWithOptions(writer, options...),
+		writer:      writer,
+	}
+	return gslog.NewLogger(handler).WithClock(DeterministicClock())
+}
+
+// testHandler 记录日志调用位置 由 testWriter 写在每行日志开头
+type testHandler struct {
+	*gslog.TextHandler
+	writer *testWriter
+}
+
+// LogRecord 实现 LogHandler
+func (h *testHandler) LogRecord(ctx context.Context, entry *gslog.LogEntry) error {
+	file, line, _ := entry.Source()
+	location := "???:1"
+	if file != "" {
+		location = filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+
+	// TextHandler 在 LogRecord 内同步写入 testWriter 加锁保证位置与日志对应
+	h.writer.recordMutex.Lock()
+	defer h.writer.recordMutex.Unlock()
+
+	h.writer.location = location
+	return h.TextHandler.LogRecord(ctx, entry)
+}
+
+// testWriter 将每行日志输出到 t.Log
+type testWriter struct {
+	mutex    sync.Mutex
+	t        testing.TB
+	finished bool
+	// 当前日志调用位置 由 testHandler 在 recordMutex 内设置
+	recordMutex sync.Mutex
+	location    string
+}
+
+// Write 实现 io.Writer 去除末尾换行后以 file:line: 开头输出到 t.Log
+func (w *testWriter) Write(data []byte) (int, error) {
+	w.t.Helper()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	// 测试结束后调用 t.Log 会 panic
+	if !w.finished {
+		w.t.Log(w.location + ": " + string(bytes.TrimSuffix(data, []byte{'\n'})))
+	}
+	return len(data), nil
+}
+
+// Sync 实现 WriteSyncer
+func (w *testWriter) Sync() error {
+	return nil
+}
+
+// Close 实现 WriteSyncer
+func (w *testWriter) Close() error {
+	return nil
+}
+
+// done 测试结束
+func (w *testWriter) done() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.finished = true
+}
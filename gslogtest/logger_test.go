@@ -0,0 +1,73 @@
+package gslogtest
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// captureTB 记录 Log 输出的 testing.TB
+type captureTB struct {
+	testing.TB
+	mutex    sync.Mutex
+	lines    []string
+	cleanups []func()
+}
+
+func (c *captureTB) Helper() {}
+
+func (c *captureTB) Log(args ...any) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.lines = append(c.lines, fmt.Sprint(args...))
+}
+
+func (c *captureTB) Cleanup(f func()) {
+	c.cleanups = append(c.cleanups, f)
+}
+
+// finish 执行 Cleanup 模拟测试结束
+func (c *captureTB) finish() {
+	for idx := len(c.cleanups) - 1; idx >= 0; idx-- {
+		c.cleanups[idx]()
+	}
+}
+
+// callerLocation 调用处 file:line
+func callerLocation() string {
+	_, file, line, _ := runtime.Caller(1)
+	return file[strings.LastIndexByte(file, '/')+1:] + ":" + strconv.Itoa(line+1)
+}
+
+func TestNewTestLoggerLocation(t *testing.T) {
+	tb := &captureTB{TB: t}
+	logger := NewTestLogger(tb)
+
+	infoAt := callerLocation()
+	logger.Info("hello", "user_id", 1)
+	withAt := callerLocation()
+	logger.With().Warnf("count %d", 2)
+
+	if len(tb.lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(tb.lines), tb.lines)
+	}
+	for idx, want := range []string{infoAt, withAt} {
+		if !strings.HasPrefix(tb.lines[idx], want+": ") {
+			t.Errorf("line %q, want prefix %q", tb.lines[idx], want+": ")
+		}
+	}
+	if !strings.Contains(tb.lines[0], "[Info] hello user_id=1") {
+		t.Errorf("line %q, want level, message and fields", tb.lines[0])
+	}
+
+	// 测试结束后的日志被丢弃
+	tb.finish()
+	logger.Info("dropped")
+	if len(tb.lines) != 2 {
+		t.Errorf("got %d lines after cleanup, want 2", len(tb.lines))
+	}
+}
@@ -0,0 +1,265 @@
+// Package gslogtest 提供测试 gslog 日志输出的工具
+// RecordingHandler 记录日志实体并提供查询与断言 NewTestLogger 将日志输出到 testing.TB
+package gslogtest
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"gslog"
+)
+
+var (
+	// 检查 RecordingHandler 实现 LogHandler
+	_ gslog.LogHandler = (*RecordingHandler)(nil)
+)
+
+// Option RecordingHandler 配置
+type Option func(handler *RecordingHandler)
+
+// WithLevel 设置记录的最低日志级别 默认记录全部级别
+func WithLevel(level gslog.LogLevel) Option {
+	return func(handler *RecordingHandler) {
+		handler.level = level
+	}
+}
+
+// WithClock 设置日志时间来源 覆盖日志实体原有时间 nil 保留原有时间
+func WithClock(clock gslog.Clock) Option {
+	return func(handler *RecordingHandler) {
+		handler.clock = clock
+	}
+}
+
+// RecordingHandler 在内存中记录日志实体 并发安全
+type RecordingHandler struct {
+	mutex   sync.Mutex
+	level   gslog.LogLevel
+	clock   gslog.Clock
+	entries []gslog.LogEntry
+}
+
+// NewRecordingHandler 创建 RecordingHandler 默认使用 DeterministicClock 保证日志时间稳定
+func NewRecordingHandler(opts ...Option) *RecordingHandler {
+	handler := &RecordingHandler{
+		level: gslog.TraceLevel,
+		clock: DeterministicClock(),
+	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	return handler
+}
+
+// Enabled 实现 LogHandler
+func (r *RecordingHandler) Enabled(ctx context.Context, level gslog.LogLevel) bool {
+	if override, ok := gslog.LevelOverrideFromContext(ctx); ok {
+		return level >= override
+	}
+	return level >= r.level
+}
+
+// LogRecord 实现 LogHandler 记录日志实体的副本
+func (r *RecordingHandler) LogRecord(_ context.Context, entry *gslog.LogEntry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	record := *entry
+	record.Fields = slices.Clone(entry.Fields)
+	if r.clock != nil {
+		record.Time = r.clock.Now()
+	}
+	r.entries = append(r.entries, record)
+	return nil
+}
+
+// Sync 实现 LogHandler
+func (r *RecordingHandler) Sync() error {
+	return nil
+}
+
+// Close 实现 LogHandler
+func (r *RecordingHandler) Close() error {
+	return nil
+}
+
+// Entries 已记录的日志实体
+func (r *RecordingHandler) Entries() Entries {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return slices.Clone(r.entries)
+}
+
+// Reset 清空已记录的日志实体
+func (r *RecordingHandler) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.entries = nil
+}
+
+// AssertLogged 断言记录过指定级别与消息的日志 并且包含全部 fields
+// 字段值按类型以及文本编码结果比较 String("n", "1") 与 Int("n", 1) 不匹配 未找到时输出已记录的日志
+func (r *RecordingHandler) AssertLogged(t testing.TB, level gslog.LogLevel, msg string, fields ...gslog.LogField) bool {
+	t.Helper()
+
+	entries := r.Entries()
+	for _, entry := range entries.FilterByLevel(level).FilterByMessage(msg) {
+		if hasFields(entry, fields) {
+			return true
+		}
+	}
+
+	var builder strings.Builder
+	for _, entry := range entries {
+		builder.WriteString("\n\t")
+		builder.WriteString(formatEntry(entry))
+	}
+	t.Errorf("gslogtest: no entry logged with level=%s msg=%q fields=%s\nrecorded entries:%s",
+		level, msg, formatFields(fields), builder.String())
+	return false
+}
+
+// AssertNotLogged 断言没有记录过指定级别与消息的日志
+func (r *RecordingHandler) AssertNotLogged(t testing.TB, level gslog.LogLevel, msg string) bool {
+	t.Helper()
+
+	if matched := r.Entries().FilterByLevel(level).FilterByMessage(msg); len(matched) > 0 {
+		t.Errorf("gslogtest: unexpected entry logged: %s", formatEntry(matched[0]))
+		return false
+	}
+	return true
+}
+
+// Entries 日志实体列表 提供查询方法
+type Entries []gslog.LogEntry
+
+// FilterByLevel 筛选指定级别的日志
+func (e Entries) FilterByLevel(level gslog.LogLevel) Entries {
+	return e.Filter(func(entry gslog.LogEntry) bool {
+		return entry.Level == level
+	})
+}
+
+// FilterByMessage 筛选指定消息的日志
+func (e Entries) FilterByMessage(msg string) Entries {
+	return e.Filter(func(entry gslog.LogEntry) bool {
+		return entry.Msg == msg
+	})
+}
+
+// FilterByField 筛选包含指定 key 的日志 key 格式同 FindField
+func (e Entries) FilterByField(key string) Entries {
+	return e.Filter(func(entry gslog.LogEntry) bool {
+		_, ok := FindField(entry, key)
+		return ok
+	})
+}
+
+// Filter 按条件筛选日志
+func (e Entries) Filter(match func(entry gslog.LogEntry) bool) Entries {
+	var filtered Entries
+	for _, entry := range e {
+		if match(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// Messages 日志消息列表
+func (e Entries) Messages() []string {
+	messages := make([]string, 0, len(e))
+	for _, entry := range e {
+		messages = append(messages, entry.Msg)
+	}
+	return messages
+}
+
+// FindField 按顺序查找第一个包含 key 的日志字段
+func (e Entries) FindField(key string) (gslog.LogField, bool) {
+	for _, entry := range e {
+		if field, ok := FindField(entry, key); ok {
+			return field, true
+		}
+	}
+	return gslog.LogField{}, false
+}
+
+// FindField 查找日志字段 key 使用 . 分隔访问 Fields 字段组内的字段 例如 request.method
+// 重复 key 返回最后一个 与 DuplicateKeepLast 保持一致
+func FindField(entry gslog.LogEntry, key string) (gslog.LogField, bool) {
+	return findField(entry.Fields, key)
+}
+
+// findField 在字段列表中查找 key
+func findField(fields []gslog.LogField, key string) (gslog.LogField, bool) {
+	var (
+		found gslog.LogField
+		ok    bool
+	)
+	for _, field := range fields {
+		if field.Key == key {
+			found, ok = field, true
+			continue
+		}
+		rest, isPrefix := strings.CutPrefix(key, field.Key+".")
+		if !isPrefix {
+			continue
+		}
+		switch field.Value.Kind() {
+		case gslog.LogFieldValueField:
+			if nested, nestedOK := findField([]gslog.LogField{field.Value.Field()}, rest); nestedOK {
+				found, ok = nested, true
+			}
+		case gslog.LogFieldValueFields:
+			if nested, nestedOK := findField(field.Value.Fields(), rest); nestedOK {
+				found, ok = nested, true
+			}
+		}
+	}
+	return found, ok
+}
+
+// hasFields 日志是否包含全部字段 字段值的类型以及文本编码结果均需一致
+func hasFields(entry gslog.LogEntry, fields []gslog.LogField) bool {
+	for _, want := range fields {
+		got, ok := FindField(entry, want.Key)
+		if !ok {
+			return false
+		}
+		gotValue, wantValue := got.Value.Resolve(), want.Value.Resolve()
+		if gotValue.Kind() != wantValue.Kind() || gotValue.String() != wantValue.String() {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldText 字段文本编码结果 用于比较
+func fieldText(field gslog.LogField) string {
+	data, err := field.MarshalText()
+	if err != nil {
+		return field.Key + "=!ERROR:" + err.Error()
+	}
+	return string(data)
+}
+
+// formatFields 格式化字段列表
+func formatFields(fields []gslog.LogField) string {
+	texts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		texts = append(texts, fieldText(field))
+	}
+	return "[" + strings.Join(texts, " ") + "]"
+}
+
+// formatEntry 格式化日志实体 用于断言失败时输出
+func formatEntry(entry gslog.LogEntry) string {
+	return entry.Level.String() + " " + strconv.Quote(entry.Msg) + " " + formatFields(entry.Fields)
+}
@@ -0,0 +1,81 @@
+package gslogtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gslog"
+)
+
+// recordTB 记录 Errorf 调用的 testing.TB
+type recordTB struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordTB) Helper() {}
+
+func (r *recordTB) Errorf(string, ...any) {
+	r.failed = true
+}
+
+func TestRecordingHandlerAssertLoggedKind(t *testing.T) {
+	handler := NewRecordingHandler()
+	logger := gslog.NewLogger(handler)
+	logger.Info("request", gslog.Int("n", 1), gslog.String("s", "1"), gslog.Fields("req", gslog.Bool("ok", true)))
+
+	tests := []struct {
+		name  string
+		field gslog.LogField
+		want  bool
+	}{
+		{name: "same kind", field: gslog.Int("n", 1), want: true},
+		{name: "int as string", field: gslog.String("n", "1"), want: false},
+		{name: "string as int", field: gslog.Int("s", 1), want: false},
+		{name: "uint as int", field: gslog.Uint("n", uint(1)), want: false},
+		{name: "nested", field: gslog.Bool("req.ok", true), want: true},
+		{name: "nested as string", field: gslog.String("req.ok", "true"), want: false},
+		{name: "lazy", field: gslog.Lazy("n", func() any { return 1 }), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := &recordTB{TB: t}
+			if got := handler.AssertLogged(tb, gslog.InfoLevel, "request", tt.field); got != tt.want || tb.failed == tt.want {
+				t.Errorf("AssertLogged(%s) = %v, want %v", fieldText(tt.field), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordingHandlerClock(t *testing.T) {
+	start := time.Date(2024, 6, 11, 10, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	handler := NewRecordingHandler(WithClock(clock))
+	logger := gslog.NewLogger(handler)
+
+	logger.Info("first")
+	clock.Advance(time.Minute)
+	logger.Info("second")
+
+	entries := handler.Entries()
+	if len(entries) != 2 || !entries[0].Time.Equal(start) || !entries[1].Time.Equal(start.Add(time.Minute)) {
+		t.Fatalf("entries = %+v", entries)
+	}
+
+	// 默认使用 DeterministicClock
+	handler = NewRecordingHandler()
+	gslog.NewLogger(handler).Log(context.Background(), gslog.InfoLevel, "a")
+	gslog.NewLogger(handler).Log(context.Background(), gslog.InfoLevel, "b")
+	entries = handler.Entries()
+	if !entries[0].Time.Equal(defaultClockStart) || !entries[1].Time.Equal(defaultClockStart.Add(defaultClockStep)) {
+		t.Errorf("default times = %v, %v", entries[0].Time, entries[1].Time)
+	}
+
+	// nil 保留日志实体原有时间
+	handler = NewRecordingHandler(WithClock(nil))
+	gslog.NewLogger(handler).WithClock(clock).Info("kept")
+	if got := handler.Entries()[0].Time; !got.Equal(clock.Now()) {
+		t.Errorf("time = %v, want logger clock %v", got, clock.Now())
+	}
+}
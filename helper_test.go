@@ -0,0 +1,66 @@
+package gslog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// bufferWriteSyncer 测试用内存 WriteSyncer
+type bufferWriteSyncer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+// Write 实现 io.Writer
+func (b *bufferWriteSyncer) Write(data []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buffer.Write(data)
+}
+
+// Sync 实现 WriteSyncer
+func (b *bufferWriteSyncer) Sync() error {
+	return nil
+}
+
+// Close 实现 WriteSyncer
+func (b *bufferWriteSyncer) Close() error {
+	return nil
+}
+
+// String 已写入内容
+func (b *bufferWriteSyncer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buffer.String()
+}
+
+// Lines 已写入的日志行
+func (b *bufferWriteSyncer) Lines() []string {
+	text := strings.TrimSuffix(b.String(), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// discardWriteSyncer 丢弃全部输出 用于性能测试
+type discardWriteSyncer struct{}
+
+// Write 实现 io.Writer
+func (discardWriteSyncer) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+// Sync 实现 WriteSyncer
+func (discardWriteSyncer) Sync() error {
+	return nil
+}
+
+// Close 实现 WriteSyncer
+func (discardWriteSyncer) Close() error {
+	return nil
+}
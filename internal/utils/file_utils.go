@@ -12,16 +12,16 @@ import (
 )
 
 // GetBackupNameByTime 根据时间获取备份新命名
-func GetBackupNameByTime(name string, layout string) string {
+func GetBackupNameByTime(name string, layout string, tm time.Time) string {
 	dir := filepath.Dir(name)
 	filename := filepath.Base(name)
 	ext := filepath.Ext(filename)
 	prefix := strings.TrimSuffix(filename, ext)
-	nowTm := time.Now()
 
-	return filepath.Join(dir, fmt.Sprintf("%s_%s%s", prefix, nowTm.Format(layout), ext))
+	return filepath.Join(dir, fmt.Sprintf("%s_%s%s", prefix, tm.Format(layout), ext))
 }
 
+// TimeFromFileName 根据备份文件名解析时间 按本地时区解析
 func TimeFromFileName(name, prefix, ext, layout string) (time.Time, error) {
 	// 前缀
 	if !strings.HasPrefix(name, prefix) {
@@ -33,7 +33,7 @@ func TimeFromFileName(name, prefix, ext, layout string) (time.Time, error) {
 	}
 
 	ts := name[len(prefix) : len(name)-len(ext)]
-	return time.Parse(layout, ts)
+	return time.ParseInLocation(layout, ts, time.Local)
 }
 
 // CompressFileByGzip 压缩文件为gzip
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetBackupNameByTime(t *testing.T) {
+	tm := time.Date(2024, 5, 6, 7, 8, 9, 10_000_000, time.Local)
+	got := GetBackupNameByTime(filepath.Join("logs", "app.log"), "2006-01-02T15-04-05.000", tm)
+	want := filepath.Join("logs", "app_2024-05-06T07-08-09.010.log")
+	if got != want {
+		t.Fatalf("GetBackupNameByTime = %q, want %q", got, want)
+	}
+}
+
+func TestTimeFromFileName(t *testing.T) {
+	const layout = "2006-01-02T15-04-05.000"
+	want := time.Date(2024, 5, 6, 7, 8, 9, 10_000_000, time.Local)
+
+	tests := []struct {
+		name    string
+		file    string
+		ext     string
+		wantErr bool
+	}{
+		{name: "plain", file: "app_2024-05-06T07-08-09.010.log", ext: ".log"},
+		{name: "gzip", file: "app_2024-05-06T07-08-09.010.log.gz", ext: ".log.gz"},
+		{name: "gzip with plain ext", file: "app_2024-05-06T07-08-09.010.log.gz", ext: ".log", wantErr: true},
+		{name: "other prefix", file: "web_2024-05-06T07-08-09.010.log", ext: ".log", wantErr: true},
+		{name: "bad time", file: "app_latest.log", ext: ".log", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TimeFromFileName(tt.file, "app_", tt.ext, layout)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("TimeFromFileName(%q) = %v, want error", tt.file, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TimeFromFileName(%q) error: %v", tt.file, err)
+			}
+			// 备份文件名按本地时间生成 解析结果需要与生成时间一致
+			if !got.Equal(want) || got.Location() != time.Local {
+				t.Fatalf("TimeFromFileName(%q) = %v, want %v", tt.file, got, want)
+			}
+		})
+	}
+}
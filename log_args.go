@@ -0,0 +1,74 @@
+package gslog
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	// 非法参数key !BADKEY[n] n为参数下标
+	badArgsKeyFormat = "!BADKEY[%d]"
+)
+
+// ArgsMode key/value 参数不合法时的处理方式
+type ArgsMode int32
+
+const (
+	ArgsLenient ArgsMode = iota // 宽松模式 非法参数命名为 !BADKEY[n] 默认
+	ArgsStrict                  // 严格模式 同宽松模式输出 并通过 Logger.WithInternalErrorHook 设置的回调上报调用位置
+	ArgsDev                     // 开发模式 直接 panic 便于在测试中发现问题
+)
+
+// argsPolicy key/value 参数不合法时的处理方式以及内部错误回调 由 Logger 持有
+type argsPolicy struct {
+	mode ArgsMode
+	// 内部错误回调 nil 输出到 os.Stderr
+	hook func(err error)
+}
+
+// report 上报内部错误
+func (a argsPolicy) report(err error) {
+	if a.hook != nil {
+		a.hook(err)
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "gslog: %v\n", err)
+}
+
+// MalformedArgsError key/value 参数不合法 携带日志调用位置
+type MalformedArgsError struct {
+	File     string
+	Line     int
+	Function string
+	// 非法参数下标
+	Index int
+	// 非法参数
+	Value  any
+	Reason string
+}
+
+// Error 实现 error 接口
+func (m *MalformedArgsError) Error() string {
+	return fmt.Sprintf("%s:%d %s: malformed log args at index %d (%v): %s",
+		m.File, m.Line, m.Function, m.Index, m.Value, m.Reason)
+}
+
+// badArgsField 按 ArgsMode 处理非法参数
+func (l *LogEntry) badArgsField(policy argsPolicy, index int, val any, reason string) LogField {
+	if mode := policy.mode; mode != ArgsLenient {
+		file, line, function := l.Source()
+		err := &MalformedArgsError{
+			File:     file,
+			Line:     line,
+			Function: function,
+			Index:    index,
+			Value:    val,
+			Reason:   reason,
+		}
+		if mode == ArgsDev {
+			panic(err)
+		}
+		policy.report(err)
+	}
+	return Any(fmt.Sprintf(badArgsKeyFormat, index), val)
+}
@@ -0,0 +1,69 @@
+package gslog
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestArgsMode(t *testing.T) {
+	writer := &bufferWriteSyncer{}
+	var reported []error
+	logger := NewLogger(NewTextHandlerWithOptions(writer, WithTextFlag(LTextLogLevel))).
+		WithInternalErrorHook(func(err error) {
+			reported = append(reported, err)
+		})
+
+	// 宽松模式 非法参数命名为 !BADKEY[n] 不上报
+	logger.Info("lenient", 42, "k", "v", "dangling")
+	if want := "[Info] lenient !BADKEY[0]=42 k=v !BADKEY[3]=dangling \n"; writer.String() != want {
+		t.Errorf("lenient output %q, want %q", writer.String(), want)
+	}
+	if len(reported) != 0 {
+		t.Errorf("lenient reported %v", reported)
+	}
+
+	// 严格模式 同宽松模式输出 上报用户调用位置
+	strict := logger.WithArgsMode(ArgsStrict)
+	_, file, line, _ := runtime.Caller(0)
+	strict.Infow("strict", "k", "v", 42, "dangling")
+	if lines := writer.Lines(); lines[len(lines)-1] != "[Info] strict k=v !BADKEY[2]=42 !BADKEY[3]=dangling " {
+		t.Errorf("strict output %q", lines[len(lines)-1])
+	}
+	if len(reported) != 2 {
+		t.Fatalf("strict reported %d errors, want 2: %v", len(reported), reported)
+	}
+	for idx, wantIndex := range []int{2, 3} {
+		var argsErr *MalformedArgsError
+		if !errors.As(reported[idx], &argsErr) {
+			t.Fatalf("reported %T, want *MalformedArgsError", reported[idx])
+		}
+		if argsErr.File != file || argsErr.Line != line+1 || argsErr.Index != wantIndex {
+			t.Errorf("reported %s:%d index %d, want %s:%d index %d", argsErr.File, argsErr.Line, argsErr.Index, file, line+1, wantIndex)
+		}
+	}
+	if reason := reported[1].(*MalformedArgsError).Reason; !strings.Contains(reason, "missing value") {
+		t.Errorf("reason = %q", reason)
+	}
+
+	// 开发模式 直接 panic
+	func() {
+		defer func() {
+			argsErr, ok := recover().(*MalformedArgsError)
+			if !ok || argsErr.Index != 0 {
+				t.Errorf("dev recovered %v, want *MalformedArgsError at index 0", argsErr)
+			}
+		}()
+		logger.WithArgsMode(ArgsDev).Info("dev", 42)
+		t.Error("dev mode did not panic")
+	}()
+
+	// 子日志器的设置不影响父日志器 合法参数不触发任何处理
+	reported = nil
+	logger.Info("parent", 42)
+	logger.WithArgsMode(ArgsDev).Info("valid", "k", 1, String("s", "v"))
+	if len(reported) != 0 || !strings.HasSuffix(writer.String(), "[Info] parent !BADKEY[0]=42 \n[Info] valid k=1 s=v \n") {
+		t.Errorf("reported %v, output %q", reported, writer.String())
+	}
+}
@@ -0,0 +1,34 @@
+package gslog
+
+import (
+	"time"
+)
+
+var (
+	// 检查 systemClock 实现 Clock
+	_ Clock = systemClock{}
+)
+
+// Clock 时间来源 Logger 以及 LogFileRollover 通过它获取当前时间 便于测试时注入固定时间
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock 系统时钟 time.Now
+type systemClock struct{}
+
+// Now 实现 Clock
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock 系统时钟 未设置 Clock 时使用
+var SystemClock Clock = systemClock{}
+
+// clockNow 获取时钟当前时间 nil 使用系统时钟
+func clockNow(clock Clock) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+	return clock.Now()
+}
@@ -0,0 +1,49 @@
+package gslog
+
+import (
+	"context"
+)
+
+// levelOverrideKey 日志级别覆盖 context key
+type levelOverrideKey struct{}
+
+// WithLevelOverride 返回携带日志级别覆盖的 context
+// 内置 LogHandler 对使用该 context 的日志调用忽略配置的日志级别 以 level 作为输出级别
+// 自定义 LogHandler 可通过 LevelOverrideFromContext 获取并决定是否采用
+// 例如针对单个请求临时开启 DebugLevel 日志
+func WithLevelOverride(ctx context.Context, level LogLevel) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, levelOverrideKey{}, level)
+}
+
+// LevelOverrideFromContext 获取 context 携带的日志级别覆盖
+func LevelOverrideFromContext(ctx context.Context) (LogLevel, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, ok := ctx.Value(levelOverrideKey{}).(LogLevel)
+	return level, ok
+}
+
+// loggerKey 日志器 context key
+type loggerKey struct{}
+
+// NewContext 返回携带日志器的 context
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext 获取 context 携带的日志器 不存在时返回全局默认日志器
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return Default()
+}
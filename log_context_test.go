@@ -0,0 +1,86 @@
+package gslog
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// strictHandler 忽略日志级别覆盖 只输出 ErrorLevel 以上日志的自定义 LogHandler
+type strictHandler struct {
+	*TextHandler
+	// Enabled 收到的日志级别覆盖
+	overrides []LogLevel
+}
+
+func (s *strictHandler) Enabled(ctx context.Context, level LogLevel) bool {
+	if override, ok := LevelOverrideFromContext(ctx); ok {
+		s.overrides = append(s.overrides, override)
+	}
+	return level >= ErrorLevel
+}
+
+func TestLevelOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    LogLevel
+		override *LogLevel
+		want     []string
+		// 不携带覆盖时是否输出 InfoLevel
+		plain bool
+	}{
+		{name: "configured", level: InfoLevel, want: []string{"[Info] info ", "[Warn] warn "}, plain: true},
+		{name: "raise", level: WarnLevel, override: ptr(DebugLevel), want: []string{"[Debug] debug ", "[Info] info ", "[Warn] warn "}, plain: false},
+		{name: "lower", level: DebugLevel, override: ptr(WarnLevel), want: []string{"[Warn] warn "}, plain: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.override != nil {
+				ctx = WithLevelOverride(ctx, *tt.override)
+			}
+			writer := &bufferWriteSyncer{}
+			logger := NewLogger(NewTextHandlerWithOptions(writer, WithLevel(tt.level), WithTextFlag(LTextLogLevel)))
+			logger.TraceContext(ctx, "trace")
+			logger.DebugContext(ctx, "debug")
+			logger.InfoContext(ctx, "info")
+			logger.WarnContext(ctx, "warn")
+			if got := writer.Lines(); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("lines = %q, want %q", got, tt.want)
+			}
+
+			// 不携带覆盖的 context 不受影响
+			writer = &bufferWriteSyncer{}
+			logger = NewLogger(NewTextHandlerWithOptions(writer, WithLevel(tt.level), WithTextFlag(LTextLogLevel)))
+			logger.InfoContext(context.Background(), "plain")
+			if got := len(writer.Lines()) == 1; got != tt.plain {
+				t.Errorf("plain info logged = %v, want %v", got, tt.plain)
+			}
+		})
+	}
+}
+
+func TestLevelOverrideCustomHandler(t *testing.T) {
+	// Logger 将覆盖交由 LogHandler 处理 自定义 LogHandler 可以拒绝
+	writer := &bufferWriteSyncer{}
+	handler := &strictHandler{TextHandler: NewTextHandlerWithOptions(writer, WithTextFlag(LTextLogLevel))}
+	logger := NewLogger(handler)
+
+	ctx := WithLevelOverride(context.Background(), DebugLevel)
+	if logger.Enabled(ctx, DebugLevel) {
+		t.Errorf("Enabled(debug) = true, want handler veto")
+	}
+	logger.DebugContext(ctx, "debug")
+	logger.ErrorContext(ctx, "error")
+
+	if got, want := writer.Lines(), []string{"[Error] error "}; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+	if len(handler.overrides) == 0 || handler.overrides[0] != DebugLevel {
+		t.Errorf("handler overrides = %v, want debug", handler.overrides)
+	}
+}
+
+func ptr[T any](val T) *T {
+	return &val
+}
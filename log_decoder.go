@@ -0,0 +1,697 @@
+package gslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gslog/internal/bufferPool"
+	"gslog/pool"
+)
+
+var (
+	// 检查 TextDecoder 实现 Decoder
+	_ Decoder = (*TextDecoder)(nil)
+	// 检查 JsonDecoder 实现 Decoder
+	_ Decoder = (*JsonDecoder)(nil)
+)
+
+var (
+	errDecodeEmptyLine = errors.New("Decoder: empty line")
+	errDecodePrefix    = errors.New("Decoder: missing text prefix")
+	errDecodeLevel     = errors.New("Decoder: missing or invalid level")
+	errDecodeSource    = errors.New("Decoder: missing or invalid source")
+	errDecodeJson      = errors.New("Decoder: invalid json entry")
+)
+
+var (
+	// 文本格式字段key key=
+	textKeyRegexp = regexp.MustCompile(`^[^\s=\[\]{},<>"]+=`)
+	// Json格式 source file:line function
+	jsonSourceRegexp = regexp.MustCompile(`^(.*):(\d+) (.*)$`)
+)
+
+const (
+	// Unix 时间戳位数判断 毫秒以及纳秒
+	unixMilliThreshold = 1e11
+	unixNanoThreshold  = 1e16
+)
+
+// Decoder 将 LogHandler 输出的一行日志解析为 DecodedEntry
+type Decoder interface {
+	Decode(line []byte) (*DecodedEntry, error)
+}
+
+// DecodedEntry 解析后的日志 字段值按输出格式推断类型
+//
+// 输出格式不携带字段类型 以下类型无法还原 解析结果如下
+//   - Uint64 不超过 int64 范围时解析为 Int64 整数值的 Float64 解析为 Int64
+//   - Int64s/Uint64s/Float64s/Strings/Bools/Array/Slice 解析为 Slice Object 解析为 Map
+//   - Binary/ByteString/IP/Prefix/URL/UUID/HardwareAddr 以及 Any 解析为输出的字符串
+//   - LogValuer 解析为 LogValue 返回值对应的类型
+//   - Error 只保留错误信息 文本格式的 key.causes/key.stack/key.verbose 解析为独立字段
+//   - Time 解析为 Local 时区 精度取决于 Layout
+//   - 文本格式中单个字段的字段组 Fields("a", String("b", v)) 输出为 a.b=v 解析为 key 为 a.b 的字段
+//   - Json格式中单个字段的字段组以及只有一个 key 的 Map 都解析为 Map
+//   - Json格式中 Duration 按 DurationEncoder 输出 默认解析为 Int64 纳秒
+//   - Json格式中符合 Layout 的字符串解析为 Time 只包含 message/causes/stack/verbose 的 Map 解析为 Error
+type DecodedEntry struct {
+	Time     time.Time
+	Level    LogLevel
+	Prefix   string
+	File     string
+	Line     int
+	Function string
+	Msg      string
+	// 字段 Json格式的静态字段位于最前
+	Fields []LogField
+}
+
+// Field 查找字段 重复 key 返回最后一个
+func (d *DecodedEntry) Field(key string) (LogField, bool) {
+	for idx := len(d.Fields) - 1; idx >= 0; idx-- {
+		if d.Fields[idx].Key == key {
+			return d.Fields[idx], true
+		}
+	}
+	return LogField{}, false
+}
+
+// AppendJSON 按 JsonHandler 的格式编码并追加到 dst opts 与 JsonHandler 的配置相同
+// Json格式的静态字段已包含在 Fields 中 配置的 StaticFields 不再输出 Prefix 不输出 没有源码位置时不输出 source
+func (d *DecodedEntry) AppendJSON(dst []byte, opts ...Options) []byte {
+	handler := NewJsonHandlerWithOptions(nil, opts...)
+	handler.options.StaticFields = nil
+	handler.refreshStaticFields()
+
+	source := ""
+	if d.File != "" {
+		source = fmt.Sprintf("%s:%d %s", d.File, d.Line, d.Function)
+	}
+	entry := &LogEntry{
+		Time:   d.Time,
+		Level:  d.Level,
+		Msg:    d.Msg,
+		Fields: d.Fields,
+	}
+
+	buffer := bufferPool.Get()
+	defer buffer.Free()
+
+	handler.encodeEntry(buffer, entry, func(buffer *pool.Buffer, entry *LogEntry, msg string, dropFields, truncated bool) {
+		handler.appendSourceEntry(buffer, entry, source, msg, dropFields, truncated)
+	})
+
+	return append(dst, buffer.Bytes()...)
+}
+
+// decodeOptions 解析使用的配置
+type decodeOptions struct {
+	options *LogOptions
+}
+
+// layout 时间格式
+func (d decodeOptions) layout() string {
+	return d.options.layout()
+}
+
+// parseTime 解析日志时间 配置了 TimeEncoder 时额外尝试常用格式以及 Unix 时间戳
+func (d decodeOptions) parseTime(val string) (time.Time, bool) {
+	if tm, err := time.ParseInLocation(d.layout(), val, time.Local); err == nil {
+		return tm, true
+	}
+	if d.options.TimeEncoder == nil {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339Nano, iso8601UTCLayout} {
+		if tm, err := time.Parse(layout, val); err == nil {
+			return tm, true
+		}
+	}
+	if num, err := strconv.ParseInt(val, 10, 64); err == nil {
+		return unixTime(num), true
+	}
+	return time.Time{}, false
+}
+
+// unixTime 按位数推断 Unix 秒/毫秒/纳秒
+func unixTime(num int64) time.Time {
+	switch {
+	case num >= unixNanoThreshold || num <= -unixNanoThreshold:
+		return time.Unix(0, num)
+	case num >= unixMilliThreshold || num <= -unixMilliThreshold:
+		return time.UnixMilli(num)
+	default:
+		return time.Unix(num, 0)
+	}
+}
+
+// TextDecoder 解析 TextHandler 输出 需要与 TextHandler 使用相同的 TextFlag/TextPrefix/Layout
+// 文本格式的字符串不加引号 消息或字符串值中包含 key= 形式的内容时无法准确区分
+type TextDecoder struct {
+	decodeOptions
+}
+
+// NewTextDecoderWithOptions 创建文本日志解析器
+func NewTextDecoderWithOptions(opts ...Options) *TextDecoder {
+	options := &LogOptions{}
+	for _, optFunc := range opts {
+		optFunc.apply(options)
+	}
+	return NewTextDecoder(options)
+}
+
+// NewTextDecoder 创建文本日志解析器
+func NewTextDecoder(options *LogOptions) *TextDecoder {
+	if options == nil {
+		options = &LogOptions{}
+	}
+	return &TextDecoder{decodeOptions{options: options}}
+}
+
+// Decode 实现 Decoder
+func (t *TextDecoder) Decode(line []byte) (*DecodedEntry, error) {
+	rest := strings.TrimRight(string(line), "\r\n")
+	if strings.TrimSpace(rest) == "" {
+		return nil, errDecodeEmptyLine
+	}
+	entry := &DecodedEntry{}
+	flag := t.options.TextFlag
+
+	// <prefix><space>
+	if prefix := t.options.TextPrefix; prefix != "" {
+		head := string(serializePrefixBegin) + prefix + string(serializePrefixEnd) + string(serializeSpaceSplit)
+		if !strings.HasPrefix(rest, head) {
+			return nil, errDecodePrefix
+		}
+		entry.Prefix = prefix
+		rest = rest[len(head):]
+	}
+	// 时间 零值时间不输出 解析失败视为不存在
+	if flag&LTextTime != 0 {
+		rest = t.decodeTime(entry, rest)
+	}
+	// [Level]<space>
+	if flag&lCheckLogLevel != 0 {
+		end := strings.IndexByte(rest, serializeArrayEnd)
+		if !strings.HasPrefix(rest, string(serializeArrayBegin)) || end < 0 {
+			return nil, errDecodeLevel
+		}
+		if err := entry.Level.UnmarshalText([]byte(rest[1:end])); err != nil {
+			return nil, errDecodeLevel
+		}
+		rest = strings.TrimPrefix(rest[end+1:], string(serializeSpaceSplit))
+	}
+	// file:line<space>
+	if flag&LTextFile != 0 {
+		token, remain, _ := strings.Cut(rest, string(serializeSpaceSplit))
+		idx := strings.LastIndexByte(token, serializeColonSplit)
+		if idx < 0 {
+			return nil, errDecodeSource
+		}
+		lineNo, err := strconv.Atoi(token[idx+1:])
+		if err != nil {
+			return nil, errDecodeSource
+		}
+		entry.File, entry.Line = token[:idx], lineNo
+		rest = remain
+	}
+	// function<space>
+	if flag&LTextFunction != 0 {
+		entry.Function, rest, _ = strings.Cut(rest, string(serializeSpaceSplit))
+	}
+
+	// message<space>fields... 可能与字段混淆的消息带引号
+	if strings.HasPrefix(rest, string(serializeStringMarks)) {
+		if quoted, err := strconv.QuotedPrefix(rest); err == nil {
+			entry.Msg, _ = strconv.Unquote(quoted)
+			rest = strings.TrimPrefix(rest[len(quoted):], string(serializeSpaceSplit))
+			if start := nextTextKey(rest, 0, false); start >= 0 {
+				entry.Fields = t.decodeFields(rest[start:])
+			}
+			return entry, nil
+		}
+	}
+	start := nextTextKey(rest, 0, false)
+	if start < 0 {
+		entry.Msg = strings.TrimSuffix(rest, string(serializeSpaceSplit))
+		return entry, nil
+	}
+	entry.Msg = strings.TrimSuffix(rest[:start], string(serializeSpaceSplit))
+	entry.Fields = t.decodeFields(rest[start:])
+	return entry, nil
+}
+
+// decodeTime 解析时间 layout 可能包含空格 逐个增加空格分隔的片段尝试解析
+func (t *TextDecoder) decodeTime(entry *DecodedEntry, rest string) string {
+	maxTokens := strings.Count(t.layout(), string(serializeSpaceSplit)) + 2
+	end := 0
+	for token := 0; token < maxTokens; token++ {
+		next := strings.IndexByte(rest[end:], serializeSpaceSplit)
+		if next < 0 {
+			break
+		}
+		end += next
+		if tm, ok := t.parseTime(rest[:end]); ok {
+			entry.Time = tm
+			return rest[end+1:]
+		}
+		end++
+	}
+	return rest
+}
+
+// decodeFields 解析 k=v k2=v2<space>
+func (t *TextDecoder) decodeFields(rest string) []LogField {
+	var fields []LogField
+	for start := 0; start < len(rest); {
+		eq := start + strings.IndexByte(rest[start:], serializeFieldStep)
+		next := nextTextKey(rest, eq+1, true)
+		end := next
+		if next < 0 {
+			end, next = len(rest), len(rest)
+		}
+		val := strings.TrimSuffix(rest[eq+1:end], string(serializeSpaceSplit))
+		fields = append(fields, LogField{Key: rest[start:eq], Value: t.decodeValue(val)})
+		start = next
+	}
+	return fields
+}
+
+// nextTextKey 从 from 开始查找下一个位于顶层且以空格分隔的 key= 位置 不存在返回 -1
+// nested 为 true 时跳过 [] {} 内部的内容 始终跳过带引号的字符串
+func nextTextKey(rest string, from int, nested bool) int {
+	depth := 0
+	for idx := from; idx < len(rest); idx++ {
+		if skip := quotedTextLength(rest, idx); skip > 0 {
+			idx += skip - 1
+			continue
+		}
+		switch rest[idx] {
+		case serializeArrayBegin, serializeJsonStart:
+			if nested {
+				depth++
+			}
+		case serializeArrayEnd, serializeJsonEnd:
+			if nested && depth > 0 {
+				depth--
+			}
+		}
+		if depth > 0 || (idx > 0 && rest[idx-1] != serializeSpaceSplit) {
+			continue
+		}
+		if textKeyRegexp.MatchString(rest[idx:]) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// decodeValue 推断文本格式字段值的类型
+func (t *TextDecoder) decodeValue(val string) LogFieldValue {
+	switch {
+	case len(val) >= 2 && val[0] == serializeStringMarks:
+		if str, err := strconv.Unquote(val); err == nil {
+			return StringFieldValue(str)
+		}
+		return StringFieldValue(val)
+	case val == "<nil>":
+		return NilFieldValue()
+	case val == "true" || val == "false":
+		return BoolFieldValue(val == "true")
+	case strings.HasPrefix(val, textErrorPrefix):
+		msg := strings.TrimPrefix(val, textErrorPrefix)
+		if unquoted, err := strconv.Unquote(msg); err == nil {
+			msg = unquoted
+		}
+		return ErrorFieldValue(errors.New(msg))
+	case len(val) >= 2 && val[0] == serializeArrayBegin && val[len(val)-1] == serializeArrayEnd:
+		return t.decodeList(val[1 : len(val)-1])
+	case len(val) >= 2 && val[0] == serializeJsonStart && val[len(val)-1] == serializeJsonEnd:
+		if fields, ok := t.decodeObject(val[1 : len(val)-1]); ok {
+			return LogFieldValue{kind: LogFieldValueMap, value: fields}
+		}
+		return StringFieldValue(val)
+	}
+	if num, ok := decodeNumber(val); ok {
+		return num
+	}
+	if tm, err := time.ParseInLocation(t.layout(), val, time.Local); err == nil {
+		return TimeFieldValue(tm)
+	}
+	if duration, err := time.ParseDuration(val); err == nil {
+		return DurationFieldValue(duration)
+	}
+	return StringFieldValue(val)
+}
+
+// decodeList 解析 [v, v2] 元素均为 k=v 时解析为 Fields 字段组
+func (t *TextDecoder) decodeList(val string) LogFieldValue {
+	elems := splitTextElements(val)
+	if fields, ok := t.decodeObject(val); ok && len(fields) > 0 {
+		return FieldArrayFieldValue(fields...)
+	}
+	values := make([]LogFieldValue, 0, len(elems))
+	for _, elem := range elems {
+		values = append(values, t.decodeValue(elem))
+	}
+	return LogFieldValue{kind: LogFieldValueSlice, value: values}
+}
+
+// decodeObject 解析 k=v, k2=v2 任一元素不是 k=v 时返回 false
+func (t *TextDecoder) decodeObject(val string) ([]LogField, bool) {
+	elems := splitTextElements(val)
+	fields := make([]LogField, 0, len(elems))
+	for _, elem := range elems {
+		if !textKeyRegexp.MatchString(elem) {
+			return nil, false
+		}
+		key, value, _ := strings.Cut(elem, string(serializeFieldStep))
+		fields = append(fields, LogField{Key: key, Value: t.decodeValue(value)})
+	}
+	return fields, true
+}
+
+// splitTextElements 按顶层 ", " 拆分容器元素
+func splitTextElements(val string) []string {
+	if val == "" {
+		return nil
+	}
+	var (
+		elems []string
+		depth int
+		start int
+	)
+	for idx := 0; idx < len(val); idx++ {
+		if skip := quotedTextLength(val, idx); skip > 0 {
+			idx += skip - 1
+			continue
+		}
+		switch val[idx] {
+		case serializeArrayBegin, serializeJsonStart:
+			depth++
+		case serializeArrayEnd, serializeJsonEnd:
+			if depth > 0 {
+				depth--
+			}
+		case serializeCommaStep:
+			if depth == 0 && idx+1 < len(val) && val[idx+1] == serializeSpaceSplit {
+				elems = append(elems, val[start:idx])
+				start = idx + 2
+				idx++
+			}
+		}
+	}
+	return append(elems, val[start:])
+}
+
+// quotedTextLength idx 处为值开头的带引号字符串时返回其长度 否则返回 0
+// 值开头即行首或位于 = 空格 [ { 之后
+func quotedTextLength(val string, idx int) int {
+	if val[idx] != serializeStringMarks {
+		return 0
+	}
+	if idx > 0 {
+		switch val[idx-1] {
+		case serializeFieldStep, serializeSpaceSplit, serializeArrayBegin, serializeJsonStart:
+		default:
+			return 0
+		}
+	}
+	quoted, err := strconv.QuotedPrefix(val[idx:])
+	if err != nil {
+		return 0
+	}
+	return len(quoted)
+}
+
+// decodeNumber 解析整数或浮点数
+func decodeNumber(val string) (LogFieldValue, bool) {
+	if num, err := strconv.ParseInt(val, 10, 64); err == nil {
+		return Int64FieldValue(num), true
+	}
+	if num, err := strconv.ParseUint(val, 10, 64); err == nil {
+		return Uint64FieldValue(num), true
+	}
+	// NaN/Inf 只接受编码器输出的形式
+	if val == "NaN" || val == "+Inf" || val == "-Inf" || strings.ContainsAny(val, "0123456789") {
+		if num, err := strconv.ParseFloat(val, 64); err == nil {
+			return Float64FieldValue(num), true
+		}
+	}
+	return LogFieldValue{}, false
+}
+
+// JsonDecoder 解析 JsonHandler 输出 需要与 JsonHandler 使用相同的 encode key 以及 Layout
+type JsonDecoder struct {
+	decodeOptions
+}
+
+// NewJsonDecoderWithOptions 创建Json日志解析器
+func NewJsonDecoderWithOptions(opts ...Options) *JsonDecoder {
+	options := &LogOptions{}
+	for _, optFunc := range opts {
+		optFunc.apply(options)
+	}
+	return NewJsonDecoder(options)
+}
+
+// NewJsonDecoder 创建Json日志解析器
+func NewJsonDecoder(options *LogOptions) *JsonDecoder {
+	if options == nil {
+		options = &LogOptions{}
+	}
+	return &JsonDecoder{decodeOptions{options: options}}
+}
+
+// Decode 实现 Decoder
+func (j *JsonDecoder) Decode(line []byte) (*DecodedEntry, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil, errDecodeEmptyLine
+	}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if tok, err := decoder.Token(); err != nil || tok != json.Delim(serializeJsonStart) {
+		return nil, errDecodeJson
+	}
+
+	entry := &DecodedEntry{}
+	for decoder.More() {
+		tok, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errDecodeJson, err)
+		}
+		key, _ := tok.(string)
+		if err = j.decodeKey(decoder, entry, key); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %w", errDecodeJson, err)
+	}
+	return entry, nil
+}
+
+// decodeKey 解析顶层key对应的值
+func (j *JsonDecoder) decodeKey(decoder *json.Decoder, entry *DecodedEntry, key string) error {
+	switch key {
+	case keyOrDefault(j.options.TimeEncodeKey, defaultJsonTimeKey):
+		var raw any
+		if err := decoder.Decode(&raw); err != nil {
+			return fmt.Errorf("%w: %w", errDecodeJson, err)
+		}
+		switch vv := raw.(type) {
+		case string:
+			entry.Time, _ = j.parseTime(vv)
+		case json.Number:
+			if num, err := vv.Int64(); err == nil {
+				entry.Time = unixTime(num)
+			}
+		}
+	case keyOrDefault(j.options.SourceEncodeKey, defaultJsonSourceKey):
+		var source string
+		if err := decoder.Decode(&source); err != nil {
+			return errDecodeSource
+		}
+		matches := jsonSourceRegexp.FindStringSubmatch(source)
+		if matches == nil {
+			return errDecodeSource
+		}
+		entry.File, entry.Function = matches[1], matches[3]
+		entry.Line, _ = strconv.Atoi(matches[2])
+	case keyOrDefault(j.options.LevelEncodeKey, defaultJsonLevelKey):
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return errDecodeLevel
+		}
+		if err := entry.Level.UnmarshalJSON(raw); err != nil {
+			return errDecodeLevel
+		}
+	case keyOrDefault(j.options.MessageEncodeKey, defaultJsonMessageKey):
+		if err := decoder.Decode(&entry.Msg); err != nil {
+			return fmt.Errorf("%w: %w", errDecodeJson, err)
+		}
+	case keyOrDefault(j.options.FieldEncodeKey, defaultJsonFieldsKey):
+		val, err := j.decodeValue(decoder)
+		if err != nil {
+			return err
+		}
+		switch val.Kind() {
+		case LogFieldValueFields:
+			entry.Fields = append(entry.Fields, val.Fields()...)
+		case LogFieldValueSlice:
+			// 空数组
+		default:
+			return errDecodeJson
+		}
+	default:
+		// 静态字段
+		val, err := j.decodeValue(decoder)
+		if err != nil {
+			return err
+		}
+		entry.Fields = append(entry.Fields, LogField{Key: key, Value: val})
+	}
+	return nil
+}
+
+// decodeValue 按顺序解析Json值并推断类型
+// 元素均为单个key对象的数组解析为 Fields 字段组 仅包含错误相关key的对象解析为错误
+func (j *JsonDecoder) decodeValue(decoder *json.Decoder) (LogFieldValue, error) {
+	tok, err := decoder.Token()
+	if err != nil {
+		return LogFieldValue{}, fmt.Errorf("%w: %w", errDecodeJson, err)
+	}
+	switch vv := tok.(type) {
+	case nil:
+		return NilFieldValue(), nil
+	case bool:
+		return BoolFieldValue(vv), nil
+	case json.Number:
+		if num, ok := decodeNumber(vv.String()); ok {
+			return num, nil
+		}
+		return StringFieldValue(vv.String()), nil
+	case string:
+		if tm, err := time.ParseInLocation(j.layout(), vv, time.Local); err == nil {
+			return TimeFieldValue(tm), nil
+		}
+		return StringFieldValue(vv), nil
+	case json.Delim:
+		if vv == serializeArrayBegin {
+			return j.decodeArray(decoder)
+		}
+		return j.decodeObject(decoder)
+	default:
+		return LogFieldValue{}, errDecodeJson
+	}
+}
+
+// decodeArray 解析数组 [ 已读取
+func (j *JsonDecoder) decodeArray(decoder *json.Decoder) (LogFieldValue, error) {
+	var values []LogFieldValue
+	group := true
+	for decoder.More() {
+		val, err := j.decodeValue(decoder)
+		if err != nil {
+			return LogFieldValue{}, err
+		}
+		if val.Kind() != LogFieldValueMap || len(val.Map()) != 1 {
+			group = false
+		}
+		values = append(values, val)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return LogFieldValue{}, fmt.Errorf("%w: %w", errDecodeJson, err)
+	}
+
+	if group && len(values) > 0 {
+		fields := make([]LogField, 0, len(values))
+		for _, val := range values {
+			fields = append(fields, val.Map()[0])
+		}
+		return FieldArrayFieldValue(fields...), nil
+	}
+	if values == nil {
+		values = []LogFieldValue{}
+	}
+	return LogFieldValue{kind: LogFieldValueSlice, value: values}, nil
+}
+
+// decodeObject 解析对象 { 已读取 保持key顺序
+func (j *JsonDecoder) decodeObject(decoder *json.Decoder) (LogFieldValue, error) {
+	var fields []LogField
+	for decoder.More() {
+		tok, err := decoder.Token()
+		if err != nil {
+			return LogFieldValue{}, fmt.Errorf("%w: %w", errDecodeJson, err)
+		}
+		key, _ := tok.(string)
+		val, err := j.decodeValue(decoder)
+		if err != nil {
+			return LogFieldValue{}, err
+		}
+		fields = append(fields, LogField{Key: key, Value: val})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return LogFieldValue{}, fmt.Errorf("%w: %w", errDecodeJson, err)
+	}
+
+	if err, ok := decodeJsonError(fields); ok {
+		return ErrorFieldValue(err), nil
+	}
+	if fields == nil {
+		fields = []LogField{}
+	}
+	return LogFieldValue{kind: LogFieldValueMap, value: fields}, nil
+}
+
+// decodeJsonError 解析 appendError 输出的 {"message":"msg","causes":[...],...}
+func decodeJsonError(fields []LogField) (error, bool) {
+	if len(fields) == 0 || fields[0].Key != errorMessageKey || fields[0].Value.Kind() != LogFieldValueString {
+		return nil, false
+	}
+	for _, field := range fields[1:] {
+		var ok bool
+		switch field.Key {
+		case errorCausesKey:
+			ok = jsonSliceOf(field.Value, LogFieldValueError, LogFieldValueNil)
+		case errorStackKey:
+			ok = jsonSliceOf(field.Value, LogFieldValueString)
+		case errorVerboseKey:
+			ok = field.Value.Kind() == LogFieldValueString
+		}
+		if !ok {
+			return nil, false
+		}
+	}
+	return errors.New(fields[0].Value.String()), true
+}
+
+// jsonSliceOf 是否为元素类型均属于 kinds 的数组 类型不符时不是错误值
+func jsonSliceOf(val LogFieldValue, kinds ...LogFieldValueKind) bool {
+	if val.Kind() != LogFieldValueSlice {
+		return false
+	}
+	for _, elem := range val.Slice() {
+		if !slices.Contains(kinds, elem.Kind()) {
+			return false
+		}
+	}
+	return true
+}
+
+// keyOrDefault 未配置时使用默认key
+func keyOrDefault(key, defaultKey string) string {
+	if key == "" {
+		return defaultKey
+	}
+	return key
+}
@@ -0,0 +1,356 @@
+package gslog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// describeValue 解析结果的类型以及值 用于比较
+func describeValue(val LogFieldValue) string {
+	switch val.Kind() {
+	case LogFieldValueFields:
+		return "Fields[" + describeFields(val.Fields()) + "]"
+	case LogFieldValueMap:
+		return "Map{" + describeFields(val.Map()) + "}"
+	case LogFieldValueSlice:
+		parts := make([]string, 0, len(val.Slice()))
+		for _, elem := range val.Slice() {
+			parts = append(parts, describeValue(elem))
+		}
+		return "Slice[" + strings.Join(parts, ",") + "]"
+	case LogFieldValueError:
+		return "Error(" + val.Error().Error() + ")"
+	case LogFieldValueNil:
+		return "Nil"
+	case LogFieldValueTime:
+		return "Time(" + val.Time().Format(time.RFC3339Nano) + ")"
+	default:
+		return fmt.Sprintf("%s(%v)", val.Kind(), val.Any())
+	}
+}
+
+// describeFields 字段列表 k=v,k2=v2
+func describeFields(fields []LogField) string {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, field.Key+"="+describeValue(field.Value))
+	}
+	return strings.Join(parts, ",")
+}
+
+// roundTrip 使用 handler 输出一条日志后用 decoder 解析
+func roundTrip(t *testing.T, newHandler func(writer WriteSyncer) LogHandler, decoder Decoder, msg string, fields ...LogField) *DecodedEntry {
+	t.Helper()
+
+	writer := &bufferWriteSyncer{}
+	NewLogger(newHandler(writer)).LogFields(context.Background(), InfoLevel, msg, fields...)
+	lines := writer.Lines()
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1: %q", len(lines), writer.String())
+	}
+	entry, err := decoder.Decode([]byte(lines[0]))
+	if err != nil {
+		t.Fatalf("Decode(%q): %v", lines[0], err)
+	}
+	return entry
+}
+
+func newDefaultTextHandler(writer WriteSyncer) LogHandler {
+	return NewTextHandlerWithOptions(writer, WithTextFlag(DefaultLTextFlag))
+}
+
+func newDefaultJsonHandler(writer WriteSyncer) LogHandler {
+	return NewJsonHandlerWithOptions(writer)
+}
+
+func TestDecoderFieldKinds(t *testing.T) {
+	tm := time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.Local)
+	link, _ := url.Parse("https://example.com/a?b=c")
+	object := ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+		enc.AddString("k", "v")
+		enc.AddInt64("n", 1)
+		return nil
+	})
+	array := ArrayMarshalerFunc(func(enc ArrayEncoder) error {
+		enc.AppendInt64(1)
+		enc.AppendString("x")
+		return nil
+	})
+
+	// 文本格式以及Json格式的解析结果 不同之处即为有损的类型
+	tests := []struct {
+		field LogField
+		text  string
+		json  string
+	}{
+		{Int("int", -3), "Int64(-3)", "Int64(-3)"},
+		{Any("ints", []int64{1, 2}), "Slice[Int64(1),Int64(2)]", "Slice[Int64(1),Int64(2)]"},
+		{Uint("uint", uint64(1)<<63), "Uint64(9223372036854775808)", "Uint64(9223372036854775808)"},
+		{Uint("small_uint", uint(7)), "Int64(7)", "Int64(7)"},
+		{Any("uints", []uint64{1}), "Slice[Int64(1)]", "Slice[Int64(1)]"},
+		{Float("float", 1.5), "Float64(1.5)", "Float64(1.5)"},
+		{Float("float_int", 2.0), "Int64(2)", "Int64(2)"},
+		{Any("floats", []float64{0.5}), "Slice[Float64(0.5)]", "Slice[Float64(0.5)]"},
+		{String("str", "hello world"), "String(hello world)", "String(hello world)"},
+		{Any("strs", []string{"a", "b, c"}), "Slice[String(a),String(b, c)]", "Slice[String(a),String(b, c)]"},
+		{Bool("bool", true), "Bool(true)", "Bool(true)"},
+		{Any("bools", []bool{true}), "Slice[Bool(true)]", "Slice[Bool(true)]"},
+		{Time("time", tm), "Time(" + tm.Format(time.RFC3339Nano) + ")", "Time(" + tm.Format(time.RFC3339Nano) + ")"},
+		{Duration("dur", 1500*time.Millisecond), "Duration(1.5s)", "Int64(1500000000)"},
+		{Fields("group", String("k", "v"), Int("n", 1)), "Fields[k=String(v),n=Int64(1)]", "Fields[k=String(v),n=Int64(1)]"},
+		{NamedError("err", fmt.Errorf("outer: %w", errors.New("inner"))), "Error(outer: inner)", "Error(outer: inner)"},
+		{Object("obj", object), "Map{k=String(v),n=Int64(1)}", "Map{k=String(v),n=Int64(1)}"},
+		{Array("arr", array), "Slice[Int64(1),String(x)]", "Slice[Int64(1),String(x)]"},
+		{Lazy("lazy", func() any { return 7 }), "Int64(7)", "Int64(7)"},
+		{Slice("slice", []any{1, "x"}), "Slice[Int64(1),String(x)]", "Slice[Int64(1),String(x)]"},
+		{Map("map", map[string]int{"a": 1, "b": 2}), "Map{a=Int64(1),b=Int64(2)}", "Map{a=Int64(1),b=Int64(2)}"},
+		{Any("nil", nil), "Nil", "Nil"},
+		{Binary("bin", []byte{1, 2}), "String(0102)", "String(AQI=)"},
+		{ByteString("bstr", []byte("bytes")), "String(bytes)", "String(bytes)"},
+		{IP("ip", netip.MustParseAddr("10.0.0.1")), "String(10.0.0.1)", "String(10.0.0.1)"},
+		{Prefix("prefix", netip.MustParsePrefix("10.0.0.0/8")), "String(10.0.0.0/8)", "String(10.0.0.0/8)"},
+		{URL("url", link), "String(https://example.com/a?b=c)", "String(https://example.com/a?b=c)"},
+		{UUID("uuid", [16]byte{1}), "String(01000000-0000-0000-0000-000000000000)", "String(01000000-0000-0000-0000-000000000000)"},
+		{HardwareAddr("mac", net.HardwareAddr{1, 2, 3, 4, 5, 6}), "String(01:02:03:04:05:06)", "String(01:02:03:04:05:06)"},
+	}
+
+	fields := make([]LogField, 0, len(tests))
+	for _, tt := range tests {
+		fields = append(fields, tt.field)
+	}
+	formats := []struct {
+		name       string
+		newHandler func(writer WriteSyncer) LogHandler
+		decoder    Decoder
+		want       func(idx int) string
+	}{
+		{"text", newDefaultTextHandler, NewTextDecoderWithOptions(WithTextFlag(DefaultLTextFlag)), func(idx int) string { return tests[idx].text }},
+		{"json", newDefaultJsonHandler, NewJsonDecoderWithOptions(), func(idx int) string { return tests[idx].json }},
+	}
+	for _, format := range formats {
+		t.Run(format.name, func(t *testing.T) {
+			entry := roundTrip(t, format.newHandler, format.decoder, "kinds", fields...)
+			for idx, tt := range tests {
+				field, ok := entry.Field(tt.field.Key)
+				if !ok {
+					t.Errorf("%s: missing in %s", tt.field.Key, describeFields(entry.Fields))
+					continue
+				}
+				if got, want := describeValue(field.Value), format.want(idx); got != want {
+					t.Errorf("%s = %s, want %s", tt.field.Key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecoderLossyGroups(t *testing.T) {
+	single := Fields("single", String("k", "v"))
+	cause := NamedError("err", fmt.Errorf("outer: %w", errors.New("inner")))
+
+	// 文本格式单个字段的字段组展开为 a.b 错误链为独立字段
+	text := roundTrip(t, newDefaultTextHandler, NewTextDecoderWithOptions(WithTextFlag(DefaultLTextFlag)), "groups", single, cause)
+	if got, want := describeFields(text.Fields), "single.k=String(v),err=Error(outer: inner),err.causes=Slice[String(inner)]"; got != want {
+		t.Errorf("text fields = %s, want %s", got, want)
+	}
+
+	// Json格式单个字段的字段组与单个key的 Map 相同 只包含 message 的 Map 解析为错误
+	json := roundTrip(t, newDefaultJsonHandler, NewJsonDecoderWithOptions(), "groups",
+		single, cause, Map("message_map", map[string]string{"message": "m"}), Map("other_map", map[string]any{"message": "m", "code": 1}))
+	want := "single=Map{k=String(v)},err=Error(outer: inner),message_map=Error(m),other_map=Map{code=Int64(1),message=String(m)}"
+	if got := describeFields(json.Fields); got != want {
+		t.Errorf("json fields = %s, want %s", got, want)
+	}
+}
+
+func TestTextDecoderMessage(t *testing.T) {
+	decoder := NewTextDecoderWithOptions(WithTextFlag(DefaultLTextFlag))
+	for _, msg := range []string{
+		"plain message",
+		"msg with k=v inside",
+		"k=v",
+		`"quoted" start`,
+		`say "hi" k=v`,
+		"multi\nline",
+		"",
+		"a=",
+	} {
+		t.Run(msg, func(t *testing.T) {
+			entry := roundTrip(t, newDefaultTextHandler, decoder, msg, String("after", "v"))
+			if entry.Msg != msg {
+				t.Errorf("Msg = %q, want %q", entry.Msg, msg)
+			}
+			if got, want := describeFields(entry.Fields), "after=String(v)"; got != want {
+				t.Errorf("fields = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestTextDecoderStrings(t *testing.T) {
+	decoder := NewTextDecoderWithOptions(WithTextFlag(DefaultLTextFlag))
+	layout := time.Now().Format(DefaultTimeLayout)
+	// 会被解析为其他类型或破坏字段拆分的字符串加引号输出
+	for _, str := range []string{
+		"42", "-1.5", "NaN", "true", "<nil>", "err: x", "1.5s", layout,
+		"[a]", "{a}", "a, b", "a b=c", `"q"`, "x\ny", "", "plain text", "a]b",
+	} {
+		t.Run(str, func(t *testing.T) {
+			entry := roundTrip(t, newDefaultTextHandler, decoder, "strings",
+				String("str", str), Any("list", []string{str}), Fields("group", String("str", str), Int("n", 1)))
+			want := fmt.Sprintf("str=String(%s),list=Slice[String(%s)],group=Fields[str=String(%s),n=Int64(1)]", str, str, str)
+			if got := describeFields(entry.Fields); got != want {
+				t.Errorf("fields = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestTextDecoderOptions(t *testing.T) {
+	opts := []Options{
+		WithTextFlag(LTextTime | LTextFile | LTextFunction | LTextLogLevelUpCase),
+		WithPrefix("app"),
+		WithLayout(time.RFC3339),
+	}
+	newHandler := func(writer WriteSyncer) LogHandler {
+		return NewTextHandlerWithOptions(writer, opts...)
+	}
+	entry := roundTrip(t, newHandler, NewTextDecoderWithOptions(opts...), "custom", String("k", "v"))
+
+	if entry.Prefix != "app" || entry.Level != InfoLevel || entry.Msg != "custom" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if entry.Time.IsZero() || time.Since(entry.Time) > time.Minute {
+		t.Errorf("Time = %v, want now", entry.Time)
+	}
+	if !strings.HasSuffix(entry.File, "log_decoder_test.go") || entry.Line <= 0 || !strings.HasSuffix(entry.Function, "roundTrip") {
+		t.Errorf("source = %s:%d %s, want roundTrip in log_decoder_test.go", entry.File, entry.Line, entry.Function)
+	}
+	if got, want := describeFields(entry.Fields), "k=String(v)"; got != want {
+		t.Errorf("fields = %s, want %s", got, want)
+	}
+
+	// 与配置不一致的行报告错误
+	if _, err := NewTextDecoderWithOptions(opts...).Decode([]byte("[INFO] no prefix")); !errors.Is(err, errDecodePrefix) {
+		t.Errorf("Decode without prefix: err = %v, want %v", err, errDecodePrefix)
+	}
+}
+
+func TestJsonDecoderOptions(t *testing.T) {
+	opts := []Options{
+		WithTimeEncodeKey("ts"),
+		WithSourceEncodeKey("caller"),
+		WithLevelEncodeKey("severity"),
+		WithMessageEncodeKey("msg"),
+		WithFieldEncodeKey("attrs"),
+		WithStaticFields(String("service", "api")),
+	}
+	newHandler := func(writer WriteSyncer) LogHandler {
+		return NewJsonHandlerWithOptions(writer, opts...)
+	}
+	entry := roundTrip(t, newHandler, NewJsonDecoderWithOptions(opts...), "custom keys", Int("n", 1))
+
+	if entry.Level != InfoLevel || entry.Msg != "custom keys" || entry.Time.IsZero() {
+		t.Errorf("entry = %+v", entry)
+	}
+	if !strings.HasSuffix(entry.File, "log_decoder_test.go") || !strings.HasSuffix(entry.Function, "roundTrip") {
+		t.Errorf("source = %s %s", entry.File, entry.Function)
+	}
+	// 静态字段位于最前
+	if got, want := describeFields(entry.Fields), "service=String(api),n=Int64(1)"; got != want {
+		t.Errorf("fields = %s, want %s", got, want)
+	}
+}
+
+func TestDecodedEntryAppendJSON(t *testing.T) {
+	opts := []Options{
+		WithTimeEncodeKey("ts"),
+		WithSourceEncodeKey("caller"),
+		WithLevelEncodeKey("severity"),
+		WithMessageEncodeKey("msg"),
+		WithFieldEncodeKey("attrs"),
+		WithTimeEncoder(UnixMilliTimeEncoder),
+	}
+	writer := &bufferWriteSyncer{}
+	NewLogger(NewJsonHandlerWithOptions(writer, opts...)).LogFields(context.Background(), WarnLevel, `say "hi"`,
+		Int("n", 1), String("s", "a\tb"), Fields("group", Bool("ok", true), Slice("list", []int{1, 2})))
+	line := writer.String()
+
+	entry, err := NewJsonDecoderWithOptions(opts...).Decode([]byte(line))
+	if err != nil {
+		t.Fatalf("Decode(%q): %v", line, err)
+	}
+	// 与 JsonHandler 输出一致
+	if got := string(entry.AppendJSON(nil, opts...)); got != line {
+		t.Errorf("AppendJSON =\n%s\nwant\n%s", got, line)
+	}
+
+	// 没有源码位置时不输出 source 静态字段只输出 Fields 中的一份
+	entry.File, entry.Line, entry.Function = "", 0, ""
+	entry.Fields = append([]LogField{String("service", "api")}, entry.Fields...)
+	got := string(entry.AppendJSON([]byte("> "), append(opts, WithStaticFields(String("service", "api")))...))
+	if !strings.HasPrefix(got, "> {") || strings.Contains(got, `"caller"`) {
+		t.Errorf("AppendJSON = %s, want dst prefix and no source", got)
+	}
+	if strings.Count(got, `"service"`) != 1 || !strings.Contains(got, `"attrs":[{"service":"api"},{"n":1}`) {
+		t.Errorf("AppendJSON = %s, want static field once in fields", got)
+	}
+}
+
+func TestJsonDecoderErrorShapedObject(t *testing.T) {
+	// message 之后的 causes/stack 类型不符时不是错误值 解析为 Map
+	tests := []struct {
+		name  string
+		field LogField
+		want  string
+	}{
+		{
+			name: "string stack",
+			field: Object("body", ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+				enc.AddString("message", "hello")
+				enc.AddString("stack", "main")
+				return nil
+			})),
+			want: "body=Map{message=String(hello),stack=String(main)}",
+		},
+		{
+			name:  "string causes",
+			field: Map("body", map[string]string{"message": "hello", "causes": "none"}),
+			want:  "body=Map{causes=String(none),message=String(hello)}",
+		},
+		{
+			name:  "object causes",
+			field: Any("body", map[string]any{"message": "hello", "causes": []map[string]int{{"a": 1}}}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := roundTrip(t, newDefaultJsonHandler, NewJsonDecoder(nil), "msg", tt.field)
+			if len(entry.Fields) != 1 || entry.Fields[0].Value.Kind() == LogFieldValueError {
+				t.Fatalf("fields = %s, want one non-error field", describeFields(entry.Fields))
+			}
+			if got := describeFields(entry.Fields); tt.want != "" && got != tt.want {
+				t.Errorf("fields = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// cmd/gslog 读取的原始日志
+	line := `{"time":"2024/06/11 10:00:00.000000","level":"info","message":"m","fields":[{"body":{"message":"hello","stack":"main"}},{"b2":{"message":"x","causes":[{"a":1},{"b":2}]}}]}`
+	entry, err := NewJsonDecoder(nil).Decode([]byte(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range entry.Fields {
+		if field.Value.Kind() != LogFieldValueMap {
+			t.Errorf("%s = %s, want Map", field.Key, describeValue(field.Value))
+		}
+	}
+}
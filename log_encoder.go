@@ -0,0 +1,222 @@
+package gslog
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// 检查 ObjectMarshalerFunc 实现 ObjectMarshaler
+	_ ObjectMarshaler = (ObjectMarshalerFunc)(nil)
+	// 检查 ArrayMarshalerFunc 实现 ArrayMarshaler
+	_ ArrayMarshaler = (ArrayMarshalerFunc)(nil)
+	// 检查 textEncoder 实现 ObjectEncoder/ArrayEncoder
+	_ ObjectEncoder = (*textEncoder)(nil)
+	_ ArrayEncoder  = (*textEncoder)(nil)
+	// 检查 jsonEncoder 实现 ObjectEncoder/ArrayEncoder
+	_ ObjectEncoder = (*jsonEncoder)(nil)
+	_ ArrayEncoder  = (*jsonEncoder)(nil)
+	// 检查 textPrimitiveEncoder 实现 PrimitiveEncoder
+	_ PrimitiveEncoder = (*textPrimitiveEncoder)(nil)
+	// 检查 jsonPrimitiveEncoder 实现 PrimitiveEncoder
+	_ PrimitiveEncoder = (*jsonPrimitiveEncoder)(nil)
+	// ArrayEncoder 同样可以作为 PrimitiveEncoder 使用
+	_ PrimitiveEncoder = (ArrayEncoder)(nil)
+)
+
+const (
+	// 截断标记 ...(N more bytes)
+	truncatedBytesFormat = "...(%d more bytes)"
+	// 数组截断标记 ...(N more elements)
+	truncatedElementsFormat = "...(%d more elements)"
+	// 超出最大嵌套深度标记
+	truncatedDepthMarker = "...(max depth exceeded)"
+)
+
+// ObjectMarshaler 自定义对象日志序列化 由 LogHandler 驱动 ObjectEncoder 写入 无需反射
+type ObjectMarshaler interface {
+	MarshalLogObject(enc ObjectEncoder) error
+}
+
+// ObjectMarshalerFunc 函数形式的 ObjectMarshaler
+type ObjectMarshalerFunc func(enc ObjectEncoder) error
+
+// MarshalLogObject 实现 ObjectMarshaler
+func (f ObjectMarshalerFunc) MarshalLogObject(enc ObjectEncoder) error {
+	return f(enc)
+}
+
+// ArrayMarshaler 自定义数组日志序列化 由 LogHandler 驱动 ArrayEncoder 写入 无需反射
+type ArrayMarshaler interface {
+	MarshalLogArray(enc ArrayEncoder) error
+}
+
+// ArrayMarshalerFunc 函数形式的 ArrayMarshaler
+type ArrayMarshalerFunc func(enc ArrayEncoder) error
+
+// MarshalLogArray 实现 ArrayMarshaler
+func (f ArrayMarshalerFunc) MarshalLogArray(enc ArrayEncoder) error {
+	return f(enc)
+}
+
+// ObjectEncoder 对象编码器 写入 key-value
+type ObjectEncoder interface {
+	AddString(key, val string)
+	AddInt64(key string, val int64)
+	AddUint64(key string, val uint64)
+	AddFloat64(key string, val float64)
+	AddBool(key string, val bool)
+	AddTime(key string, val time.Time)
+	AddDuration(key string, val time.Duration)
+	AddObject(key string, val ObjectMarshaler) error
+	AddArray(key string, val ArrayMarshaler) error
+	// AddAny 按 AnyFieldValue 规则写入任意类型
+	AddAny(key string, val any) error
+}
+
+// ArrayEncoder 数组编码器 顺序写入元素
+type ArrayEncoder interface {
+	AppendString(val string)
+	AppendInt64(val int64)
+	AppendUint64(val uint64)
+	AppendFloat64(val float64)
+	AppendBool(val bool)
+	AppendTime(val time.Time)
+	AppendDuration(val time.Duration)
+	AppendObject(val ObjectMarshaler) error
+	AppendArray(val ArrayMarshaler) error
+	// AppendAny 按 AnyFieldValue 规则写入任意类型
+	AppendAny(val any) error
+}
+
+// PrimitiveEncoder 基础类型编码器 TimeEncoder/DurationEncoder/LevelEncoder 通过它写入单个值
+// 文本格式直接写入 Json格式字符串会加引号并转义
+type PrimitiveEncoder interface {
+	AppendString(val string)
+	AppendInt64(val int64)
+	AppendFloat64(val float64)
+}
+
+// truncateBinary 按最大长度截断二进制数据 返回截断后的数据以及被截断的长度
+func truncateBinary(val []byte, maxLength int) ([]byte, int) {
+	if maxLength <= 0 || len(val) <= maxLength {
+		return val, 0
+	}
+	return val[:maxLength], len(val) - maxLength
+}
+
+// truncateString 按最大长度截断字符串 返回截断后的字符串以及被截断的长度 0不限制
+func truncateString(val string, maxLength int) (string, int) {
+	if maxLength <= 0 || len(val) <= maxLength {
+		return val, 0
+	}
+	return cutString(val, maxLength)
+}
+
+// cutString 在不超过 length 的 utf8 字符边界处截断字符串
+func cutString(val string, length int) (string, int) {
+	if length >= len(val) {
+		return val, 0
+	}
+	if length < 0 {
+		length = 0
+	}
+	for length > 0 && !utf8.RuneStart(val[length]) {
+		length--
+	}
+	return val[:length], len(val) - length
+}
+
+// arrayState 数组写入状态 嵌套数组开始时保存 结束时恢复
+type arrayState struct {
+	elements int
+	dropped  int
+}
+
+// nestedKind 需要计算嵌套深度的容器类型 Object/Array 由 appendObject/appendArray 计算
+func nestedKind(kind LogFieldValueKind) bool {
+	switch kind {
+	case LogFieldValueInt64s, LogFieldValueUint64s, LogFieldValueFloat64s, LogFieldValueStrings, LogFieldValueBools,
+		LogFieldValueField, LogFieldValueFields, LogFieldValueSlice, LogFieldValueMap:
+		return true
+	default:
+		return false
+	}
+}
+
+// appendUUID 写入标准格式 UUID xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+func appendUUID(dst []byte, val [16]byte) []byte {
+	for idx, b := range val {
+		if idx == 4 || idx == 6 || idx == 8 || idx == 10 {
+			dst = append(dst, '-')
+		}
+		dst = append(dst, jsonHex[b>>4], jsonHex[b&0xF])
+	}
+	return dst
+}
+
+// appendNetworkValue 写入 IP/CIDR/URL/UUID/MAC 的标准格式
+func appendNetworkValue(dst []byte, val LogFieldValue) []byte {
+	switch val.Kind() {
+	case LogFieldValueIP:
+		if addr := val.IP(); addr.IsValid() {
+			return addr.AppendTo(dst)
+		}
+		return append(dst, val.IP().String()...)
+	case LogFieldValuePrefix:
+		if prefix := val.Prefix(); prefix.IsValid() {
+			return prefix.AppendTo(dst)
+		}
+		return append(dst, val.Prefix().String()...)
+	case LogFieldValueURL:
+		return append(dst, val.URL().String()...)
+	case LogFieldValueUUID:
+		return appendUUID(dst, val.UUID())
+	case LogFieldValueHardwareAddr:
+		for idx, b := range val.HardwareAddr() {
+			if idx > 0 {
+				dst = append(dst, serializeColonSplit)
+			}
+			dst = append(dst, jsonHex[b>>4], jsonHex[b&0xF])
+		}
+		return dst
+	default:
+		return dst
+	}
+}
+
+// textPrimitiveEncoder 文本格式基础类型编码器 不写入分隔符 不计入数组元素
+type textPrimitiveEncoder textEncoder
+
+// AppendString 实现 PrimitiveEncoder
+func (t *textPrimitiveEncoder) AppendString(val string) {
+	t.buffer.AppendString(val)
+}
+
+// AppendInt64 实现 PrimitiveEncoder
+func (t *textPrimitiveEncoder) AppendInt64(val int64) {
+	t.buffer.AppendInt(val)
+}
+
+// AppendFloat64 实现 PrimitiveEncoder
+func (t *textPrimitiveEncoder) AppendFloat64(val float64) {
+	t.buffer.AppendFloat(val, 64)
+}
+
+// jsonPrimitiveEncoder Json格式基础类型编码器 不写入分隔符 不计入数组元素
+type jsonPrimitiveEncoder jsonEncoder
+
+// AppendString 实现 PrimitiveEncoder
+func (j *jsonPrimitiveEncoder) AppendString(val string) {
+	appendJsonString(j.buffer, val)
+}
+
+// AppendInt64 实现 PrimitiveEncoder
+func (j *jsonPrimitiveEncoder) AppendInt64(val int64) {
+	j.buffer.AppendInt(val)
+}
+
+// AppendFloat64 实现 PrimitiveEncoder
+func (j *jsonPrimitiveEncoder) AppendFloat64(val float64) {
+	(*jsonEncoder)(j).appendFloat(val)
+}
@@ -0,0 +1,95 @@
+package gslog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testUser ObjectMarshaler 测试类型 覆盖全部 ObjectEncoder 方法
+type testUser struct {
+	name string
+	tags []string
+}
+
+func (u testUser) MarshalLogObject(enc ObjectEncoder) error {
+	enc.AddString("name", u.name)
+	enc.AddInt64("age", -3)
+	enc.AddUint64("id", 7)
+	enc.AddFloat64("score", 1.5)
+	enc.AddBool("admin", true)
+	enc.AddTime("created", time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC))
+	enc.AddDuration("ttl", 1500*time.Millisecond)
+	if err := enc.AddArray("tags", testTags(u.tags)); err != nil {
+		return err
+	}
+	if err := enc.AddObject("nested", ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+		enc.AddString("k", "v")
+		return nil
+	})); err != nil {
+		return err
+	}
+	return enc.AddAny("extra", map[string]any{"b": 2, "a": 1})
+}
+
+// testTags ArrayMarshaler 测试类型
+type testTags []string
+
+func (t testTags) MarshalLogArray(enc ArrayEncoder) error {
+	for _, tag := range t {
+		enc.AppendString(tag)
+	}
+	return nil
+}
+
+// testAllArray 覆盖全部 ArrayEncoder 方法
+var testAllArray = ArrayMarshalerFunc(func(enc ArrayEncoder) error {
+	enc.AppendString("s")
+	enc.AppendInt64(-1)
+	enc.AppendUint64(2)
+	enc.AppendFloat64(0.25)
+	enc.AppendBool(false)
+	enc.AppendTime(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC))
+	enc.AppendDuration(time.Second)
+	if err := enc.AppendObject(testUser{name: "x"}); err != nil {
+		return err
+	}
+	if err := enc.AppendArray(testTags{"a", "b"}); err != nil {
+		return err
+	}
+	return enc.AppendAny([]int{1, 2})
+})
+
+func TestMarshalerOutput(t *testing.T) {
+	failing := ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+		enc.AddString("partial", "yes")
+		return errors.New("marshal failed")
+	})
+	fields := []any{
+		Object("user", testUser{name: "alice", tags: []string{"x", "y"}}),
+		Array("all", testAllArray),
+		Object("nil", nil),
+		Array("nilArray", nil),
+		Object("failing", failing),
+	}
+
+	textWriter, jsonWriter := &bufferWriteSyncer{}, &bufferWriteSyncer{}
+	NewLogger(NewTextHandlerWithOptions(textWriter, WithTextFlag(LTextLogLevel))).Info("msg", fields...)
+	NewLogger(NewJsonHandlerWithOptions(jsonWriter)).Info("msg", fields...)
+	user := "{name=alice, age=-3, id=7, score=1.5, admin=true, created=2024/05/06 07:08:09.000000, ttl=1.5s, tags=[x, y], nested={k=v}, extra={a=1, b=2}}"
+	inner := "{name=x, age=-3, id=7, score=1.5, admin=true, created=2024/05/06 07:08:09.000000, ttl=1.5s, tags=[], nested={k=v}, extra={a=1, b=2}}"
+	wantText := "[Info] msg user=" + user + " all=[s, -1, 2, 0.25, false, 2024/05/06 07:08:09.000000, 1s, " + inner + ", [a, b], [1, 2]]" +
+		" nil=<nil> nilArray=<nil> failing={partial=yes} failingError=marshal failed \n"
+	if got := textWriter.String(); got != wantText {
+		t.Errorf("text output\n got %q\nwant %q", got, wantText)
+	}
+
+	user = `{"name":"alice","age":-3,"id":7,"score":1.5,"admin":true,"created":"2024/05/06 07:08:09.000000","ttl":1500000000,"tags":["x","y"],"nested":{"k":"v"},"extra":{"a":1,"b":2}}`
+	inner = `{"name":"x","age":-3,"id":7,"score":1.5,"admin":true,"created":"2024/05/06 07:08:09.000000","ttl":1500000000,"tags":[],"nested":{"k":"v"},"extra":{"a":1,"b":2}}`
+	wantJson := `"fields":[{"user":` + user + `},{"all":["s",-1,2,0.25,false,"2024/05/06 07:08:09.000000",1000000000,` + inner + `,["a","b"],[1,2]]},` +
+		`{"nil":null},{"nilArray":null},{"failing":{"partial":"yes"},"failingError":"marshal failed"}]}` + "\n"
+	if got := jsonWriter.String(); !strings.HasSuffix(got, wantJson) {
+		t.Errorf("json output\n got %s\nwant ...%s", got, wantJson)
+	}
+}
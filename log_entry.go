@@ -37,25 +37,33 @@ func (l *LogEntry) Source() (file string, line int, function string) {
 	return frame.File, frame.Line, frame.Function
 }
 
-// AddArgs 添加参数
+// AddArgs 添加参数 key/value 交替传入 或直接传入 LogField
+// 参数不合法时按 ArgsLenient 处理 Logger 按 Logger.WithArgsMode 设置的方式处理
 func (l *LogEntry) AddArgs(args ...any) {
+	l.addArgs(argsPolicy{}, args...)
+}
+
+// addArgs 添加参数 参数不合法时按 policy 处理
+func (l *LogEntry) addArgs(policy argsPolicy, args ...any) {
 	var field LogField
-	for len(args) > 0 {
-		field, args = l.argsToLogFields(args...)
+	for index := 0; len(args) > 0; {
+		remain := len(args)
+		field, args = l.argsToLogFields(policy, index, args...)
 		l.AppendFields(field)
+		index += remain - len(args)
 	}
 }
 
-func (l *LogEntry) argsToLogFields(args ...any) (LogField, []any) {
+func (l *LogEntry) argsToLogFields(policy argsPolicy, index int, args ...any) (LogField, []any) {
 	switch vv := args[0].(type) {
 	case LogField:
 		return vv, args[1:]
 	case string:
 		if len(args) <= 1 {
-			return String[string](badFieldsKey, vv), nil
+			return l.badArgsField(policy, index, vv, "missing value for key"), nil
 		}
-		return Any(vv, args[1:]), args[2:]
+		return Any(vv, args[1]), args[2:]
 	default:
-		return Any(badFieldsKey, vv), args[1:]
+		return l.badArgsField(policy, index, vv, "key is not a string or LogField"), args[1:]
 	}
 }
@@ -0,0 +1,153 @@
+package gslog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+)
+
+var (
+	// 检查 stackError 实现 ErrorCallers
+	_ ErrorCallers = (*stackError)(nil)
+	// 检查 stackError 实现 fmt.Formatter
+	_ fmt.Formatter = (*stackError)(nil)
+)
+
+const (
+	// 错误链最大展开深度
+	maxErrorCauseDepth = 32
+	// 调用栈最大深度
+	maxErrorStackDepth = 64
+
+	errorMessageKey = "message"
+	errorCausesKey  = "causes"
+	errorStackKey   = "stack"
+	errorVerboseKey = "verbose"
+
+	// 文本格式错误值前缀
+	textErrorPrefix = "err: "
+)
+
+// ErrorCallers 携带调用栈的错误 JsonHandler 会输出对应调用栈
+type ErrorCallers interface {
+	error
+	Callers() []uintptr
+}
+
+// stackError 携带创建时调用栈的错误
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+// WithStack 为错误附加当前调用栈 err 为 nil 时返回 nil
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	var pcs [maxErrorStackDepth]uintptr
+	// runtime.Callers, this function
+	n := runtime.Callers(2, pcs[:])
+	return &stackError{
+		err: err,
+		pcs: pcs[:n],
+	}
+}
+
+// Error 实现 error
+func (s *stackError) Error() string {
+	return s.err.Error()
+}
+
+// Unwrap 支持 errors.Is/errors.As
+func (s *stackError) Unwrap() error {
+	return s.err
+}
+
+// Callers 实现 ErrorCallers
+func (s *stackError) Callers() []uintptr {
+	return s.pcs
+}
+
+// Format 实现 fmt.Formatter %+v 输出错误以及调用栈
+func (s *stackError) Format(state fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if state.Flag('+') {
+			_, _ = io.WriteString(state, s.Error())
+			frames := runtime.CallersFrames(s.pcs)
+			for {
+				frame, more := frames.Next()
+				_, _ = fmt.Fprintf(state, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+				if !more {
+					break
+				}
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(state, s.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(state, "%q", s.Error())
+	}
+}
+
+// isNilError 判断 error 是否为 nil 包括携带 nil 指针的 error 接口
+func isNilError(err error) bool {
+	if err == nil {
+		return true
+	}
+	val := reflect.ValueOf(err)
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return val.IsNil()
+	default:
+		return false
+	}
+}
+
+// errorCauses 获取下一层错误 跳过只附加调用栈等信息而不改变错误信息的单层包装 避免重复输出相同信息
+func errorCauses(err error) []error {
+	for depth := 0; depth < maxErrorCauseDepth; depth++ {
+		var causes []error
+		switch vv := err.(type) {
+		case interface{ Unwrap() []error }:
+			causes = vv.Unwrap()
+		case interface{ Unwrap() error }:
+			if cause := vv.Unwrap(); cause != nil {
+				causes = []error{cause}
+			}
+		}
+		if len(causes) != 1 || isNilError(causes[0]) || causes[0].Error() != err.Error() {
+			return causes
+		}
+		err = causes[0]
+	}
+	return nil
+}
+
+// errorCallers 错误链中第一个携带调用栈的错误
+func errorCallers(err error) (ErrorCallers, bool) {
+	var callers ErrorCallers
+	if errors.As(err, &callers) && !isNilError(callers) {
+		return callers, true
+	}
+	return nil, false
+}
+
+// errorVerbose %+v 输出与 Error() 不同时返回详细信息 例如 github.com/pkg/errors 携带的调用栈
+func errorVerbose(err error) (string, bool) {
+	if _, ok := err.(fmt.Formatter); !ok {
+		return "", false
+	}
+	verbose := fmt.Sprintf("%+v", err)
+	return verbose, verbose != err.Error()
+}
+
+// formatErrorFrame 调用栈单帧 function file:line
+func formatErrorFrame(frame runtime.Frame) string {
+	return fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line)
+}
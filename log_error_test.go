@@ -0,0 +1,148 @@
+package gslog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testPtrError 指针接收者实现的错误 用于构造携带 nil 指针的 error
+type testPtrError struct {
+	msg string
+}
+
+func (e *testPtrError) Error() string {
+	return e.msg
+}
+
+// marshalErrorText LogField 文本格式输出
+func marshalErrorText(t *testing.T, field LogField) string {
+	t.Helper()
+
+	data, err := field.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	return string(data)
+}
+
+// marshalErrorJson LogField Json 格式输出 解析为 error 对象
+func marshalErrorJson(t *testing.T, field LogField) any {
+	t.Helper()
+
+	data, err := field.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	var obj map[string]any
+	if err = json.Unmarshal(data, &obj); err != nil {
+		t.Fatalf("invalid json %s: %v", data, err)
+	}
+	return obj[field.Key]
+}
+
+func TestErrorTypedNil(t *testing.T) {
+	var ptr *testPtrError
+	field := NamedError("err", ptr)
+
+	if got, want := marshalErrorText(t, field), "err=err: <nil>"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+	if got := marshalErrorJson(t, field); got != nil {
+		t.Errorf("json = %v, want null", got)
+	}
+
+	// errors.Join 中的 nil 指针
+	field = NamedError("err", errors.Join(errors.New("first"), ptr))
+	if got, want := marshalErrorText(t, field), "err=err: [first, <nil>]"; got != want {
+		t.Errorf("joined text = %q, want %q", got, want)
+	}
+}
+
+func TestErrorJoinTree(t *testing.T) {
+	base := errors.New("base")
+	wrapped := fmt.Errorf("wrapped: %w", base)
+	tree := errors.Join(wrapped, errors.Join(errors.New("left"), errors.New("right")))
+	field := NamedError("err", tree)
+
+	text := marshalErrorText(t, field)
+	if want := `err=err: [wrapped: base, "left\nright"]`; !strings.HasPrefix(text, want) {
+		t.Errorf("text = %q, want prefix %q", text, want)
+	}
+	if want := " err.causes=[base, left, right]"; !strings.Contains(text, want) {
+		t.Errorf("text = %q, want causes %q", text, want)
+	}
+	if strings.ContainsAny(text, "\r\n") {
+		t.Errorf("text = %q, want single line", text)
+	}
+
+	obj, _ := marshalErrorJson(t, field).(map[string]any)
+	causes, _ := obj[errorCausesKey].([]any)
+	if len(causes) != 2 {
+		t.Fatalf("json causes = %v, want 2", obj[errorCausesKey])
+	}
+	first, _ := causes[0].(map[string]any)
+	if first[errorMessageKey] != "wrapped: base" {
+		t.Errorf("json causes[0] = %v", first)
+	}
+	inner, _ := first[errorCausesKey].([]any)
+	if len(inner) != 1 || inner[0].(map[string]any)[errorMessageKey] != "base" {
+		t.Errorf("json causes[0].causes = %v, want [base]", inner)
+	}
+	second, _ := causes[1].(map[string]any)
+	if nested, _ := second[errorCausesKey].([]any); len(nested) != 2 {
+		t.Errorf("json causes[1].causes = %v, want left and right", second[errorCausesKey])
+	}
+}
+
+func TestErrorWithStack(t *testing.T) {
+	err := WithStack(errors.New("boom"))
+	field := NamedError("err", err)
+
+	obj, _ := marshalErrorJson(t, field).(map[string]any)
+	if obj[errorMessageKey] != "boom" {
+		t.Errorf("json message = %v, want boom", obj[errorMessageKey])
+	}
+	// 调用栈包装不改变错误信息 不再重复输出
+	if causes, ok := obj[errorCausesKey]; ok {
+		t.Errorf("json causes = %v, want none", causes)
+	}
+	stack, _ := obj[errorStackKey].([]any)
+	if len(stack) == 0 || !strings.Contains(stack[0].(string), "TestErrorWithStack") {
+		t.Errorf("json stack = %v, want frames from TestErrorWithStack", obj[errorStackKey])
+	}
+
+	text := marshalErrorText(t, field)
+	if !strings.HasPrefix(text, "err=err: boom err.stack=[") || !strings.Contains(text, "TestErrorWithStack") {
+		t.Errorf("text = %q, want stack after message", text)
+	}
+	if strings.Contains(text, "err.causes=") {
+		t.Errorf("text = %q, want no causes", text)
+	}
+}
+
+func TestErrorCauseChainText(t *testing.T) {
+	err := fmt.Errorf("outer: %w", fmt.Errorf("middle: %w", errors.New("inner")))
+	text := marshalErrorText(t, NamedError("err", err))
+
+	want := "err=err: outer: middle: inner err.causes=[middle: inner, inner]"
+	if text != want {
+		t.Errorf("text = %q, want %q", text, want)
+	}
+
+	// 字段组内使用逗号分隔
+	text = marshalErrorText(t, Fields("group", NamedError("err", err), String("k", "v")))
+	want = "group=[err=err: outer: middle: inner, err.causes=[middle: inner, inner], k=v]"
+	if text != want {
+		t.Errorf("grouped text = %q, want %q", text, want)
+	}
+
+	// 嵌套 key 使用完整前缀
+	text = marshalErrorText(t, Fields("group", NamedError("err", err)))
+	want = "group.err=err: outer: middle: inner group.err.causes=[middle: inner, inner]"
+	if text != want {
+		t.Errorf("nested text = %q, want %q", text, want)
+	}
+}
@@ -0,0 +1,97 @@
+package gslog
+
+import (
+	"sort"
+)
+
+// arrangedField 去重排列后的字段 static 表示来自 LogOptions.StaticFields
+type arrangedField struct {
+	LogField
+	static bool
+}
+
+// arrangeEnabled 是否配置了去重或排列
+func arrangeEnabled(options *LogOptions) bool {
+	return options.DuplicateKeys != DuplicateKeepAll || options.SortFields || len(options.LeadingKeys) > 0
+}
+
+// arrangeFields 按配置去重并排列字段 不修改传入的切片
+func arrangeFields(fields []LogField, options *LogOptions) []LogField {
+	if len(fields) <= 1 || !arrangeEnabled(options) {
+		return fields
+	}
+	order := arrangeFieldOrder(fields, options)
+	arranged := make([]LogField, 0, len(order))
+	for _, idx := range order {
+		arranged = append(arranged, fields[idx])
+	}
+	return arranged
+}
+
+// arrangeEntryFields 静态字段与日志字段合并后统一去重排列 静态字段在前
+// 未配置去重或排列 或者没有静态字段时返回 nil 静态字段使用预先编码的结果
+func arrangeEntryFields(statics, fields []LogField, options *LogOptions) []arrangedField {
+	if len(statics) == 0 || len(fields) == 0 || !arrangeEnabled(options) {
+		return nil
+	}
+	combined := make([]LogField, 0, len(statics)+len(fields))
+	combined = append(append(combined, statics...), fields...)
+	order := arrangeFieldOrder(combined, options)
+	arranged := make([]arrangedField, 0, len(order))
+	for _, idx := range order {
+		arranged = append(arranged, arrangedField{LogField: combined[idx], static: idx < len(statics)})
+	}
+	return arranged
+}
+
+// arrangeFieldOrder 按配置去重并排列字段 返回保留字段的下标
+func arrangeFieldOrder(fields []LogField, options *LogOptions) []int {
+	order := make([]int, 0, len(fields))
+	switch options.DuplicateKeys {
+	case DuplicateKeepFirst:
+		seen := make(map[string]struct{}, len(fields))
+		for idx, field := range fields {
+			if _, ok := seen[field.Key]; ok {
+				continue
+			}
+			seen[field.Key] = struct{}{}
+			order = append(order, idx)
+		}
+	case DuplicateKeepLast:
+		last := make(map[string]int, len(fields))
+		for idx, field := range fields {
+			last[field.Key] = idx
+		}
+		for idx, field := range fields {
+			if last[field.Key] == idx {
+				order = append(order, idx)
+			}
+		}
+	default:
+		for idx := range fields {
+			order = append(order, idx)
+		}
+	}
+
+	if !options.SortFields && len(options.LeadingKeys) == 0 {
+		return order
+	}
+	// 优先输出的key排在最前 其余字段按需按key排序 相同key保持原有顺序
+	rank := func(key string) int {
+		for idx, leading := range options.LeadingKeys {
+			if leading == key {
+				return idx
+			}
+		}
+		return len(options.LeadingKeys)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		keyI, keyJ := fields[order[i]].Key, fields[order[j]].Key
+		ri, rj := rank(keyI), rank(keyJ)
+		if ri != rj {
+			return ri < rj
+		}
+		return options.SortFields && keyI < keyJ
+	})
+	return order
+}
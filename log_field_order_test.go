@@ -0,0 +1,86 @@
+package gslog
+
+import (
+	"strings"
+	"testing"
+)
+
+// fieldKeys 字段 key 以及值 k=v
+func fieldKeys(fields []LogField) string {
+	pairs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		pairs = append(pairs, field.Key+"="+field.Value.String())
+	}
+	return strings.Join(pairs, " ")
+}
+
+func TestArrangeFields(t *testing.T) {
+	fields := []LogField{String("b", "1"), String("a", "2"), String("c", "3"), String("a", "4"), String("b", "5")}
+	tests := []struct {
+		name string
+		opts []Options
+		want string
+	}{
+		{"keep all", nil, "b=1 a=2 c=3 a=4 b=5"},
+		{"keep first", []Options{WithDuplicateKeys(DuplicateKeepFirst)}, "b=1 a=2 c=3"},
+		{"keep last", []Options{WithDuplicateKeys(DuplicateKeepLast)}, "c=3 a=4 b=5"},
+		{"sort", []Options{WithSortFields(true)}, "a=2 a=4 b=1 b=5 c=3"},
+		{"leading", []Options{WithLeadingKeys("c", "a")}, "c=3 a=2 a=4 b=1 b=5"},
+		{"keep last sort leading", []Options{WithDuplicateKeys(DuplicateKeepLast), WithSortFields(true), WithLeadingKeys("c")}, "c=3 a=4 b=5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := &LogOptions{}
+			for _, opt := range tt.opts {
+				opt.apply(options)
+			}
+			if got := fieldKeys(arrangeFields(fields, options)); got != tt.want {
+				t.Errorf("arrangeFields = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := fieldKeys(fields); got != "b=1 a=2 c=3 a=4 b=5" {
+		t.Errorf("arrangeFields modified input: %q", got)
+	}
+}
+
+func TestArrangeFieldsHandler(t *testing.T) {
+	static := WithStaticFields(String("service", "static"), String("env", "prod"))
+	fields := []any{String("service", "call"), Int("b", 1), Fields("group", Int("y", 1), Int("x", 2), Int("y", 3)), Int("a", 2)}
+	tests := []struct {
+		name string
+		opts []Options
+		text string
+		json string
+	}{
+		{"keep all", nil,
+			"service=static env=prod service=call b=1 group=[y=1, x=2, y=3] a=2",
+			`"service":"static","env":"prod","fields":[{"service":"call"},{"b":1},{"group":[{"y":1},{"x":2},{"y":3}]},{"a":2}]`},
+		{"keep first", []Options{WithDuplicateKeys(DuplicateKeepFirst)},
+			"service=static env=prod b=1 group=[y=1, x=2] a=2",
+			`"service":"static","env":"prod","fields":[{"b":1},{"group":[{"y":1},{"x":2}]},{"a":2}]`},
+		{"keep last", []Options{WithDuplicateKeys(DuplicateKeepLast)},
+			"env=prod service=call b=1 group=[x=2, y=3] a=2",
+			`"env":"prod","fields":[{"service":"call"},{"b":1},{"group":[{"x":2},{"y":3}]},{"a":2}]`},
+		{"sort", []Options{WithSortFields(true)},
+			"a=2 b=1 env=prod group=[x=2, y=1, y=3] service=static service=call",
+			`"env":"prod","service":"static","fields":[{"a":2},{"b":1},{"group":[{"x":2},{"y":1},{"y":3}]},{"service":"call"}]`},
+		{"leading", []Options{WithLeadingKeys("a", "y", "service")},
+			"a=2 service=static service=call env=prod b=1 group=[y=1, y=3, x=2]",
+			`"service":"static","env":"prod","fields":[{"a":2},{"service":"call"},{"b":1},{"group":[{"y":1},{"y":3},{"x":2}]}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			textWriter, jsonWriter := &bufferWriteSyncer{}, &bufferWriteSyncer{}
+			NewLogger(NewTextHandlerWithOptions(textWriter, append([]Options{WithTextFlag(LTextLogLevel), static}, tt.opts...)...)).Info("msg", fields...)
+			NewLogger(NewJsonHandlerWithOptions(jsonWriter, append([]Options{static}, tt.opts...)...)).Info("msg", fields...)
+
+			if got, want := textWriter.String(), "[Info] msg "+tt.text+" \n"; got != want {
+				t.Errorf("text output\n got %q\nwant %q", got, want)
+			}
+			if got := jsonWriter.String(); !strings.Contains(got, `"message":"msg",`+tt.json+"}\n") {
+				t.Errorf("json output\n got %s\nwant ...%s}", got, tt.json)
+			}
+		})
+	}
+}
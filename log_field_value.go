@@ -1,10 +1,14 @@
 package gslog
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
-	"strconv"
-	"strings"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"sort"
 	"time"
 
 	"gslog/internal/bufferPool"
@@ -30,29 +34,77 @@ const (
 	LogFieldValueField
 	LogFieldValueFields
 	LogFieldValueError
+	LogFieldValueObject
+	LogFieldValueArray
+	LogFieldValueLogValuer
+	LogFieldValueSlice
+	LogFieldValueMap
+	LogFieldValueNil
+	LogFieldValueBinary
+	LogFieldValueByteString
+	LogFieldValueIP
+	LogFieldValuePrefix
+	LogFieldValueURL
+	LogFieldValueUUID
+	LogFieldValueHardwareAddr
 )
 
 var (
 	kindStrings = map[LogFieldValueKind]string{
-		LogFieldValueAny:      "Any",
-		LogFieldValueInt64:    "Int64",
-		LogFieldValueInt64s:   "Int64s",
-		LogFieldValueUint64:   "Uint64",
-		LogFieldValueUint64s:  "Uint64s",
-		LogFieldValueFloat64:  "Float64",
-		LogFieldValueFloat64s: "Floats",
-		LogFieldValueString:   "String",
-		LogFieldValueStrings:  "Strings",
-		LogFieldValueBool:     "Bool",
-		LogFieldValueBools:    "Bools",
-		LogFieldValueTime:     "Time",
-		LogFieldValueDuration: "Duration",
-		LogFieldValueField:    "Field",
-		LogFieldValueFields:   "Fields",
-		LogFieldValueError:    "Error",
+		LogFieldValueAny:          "Any",
+		LogFieldValueInt64:        "Int64",
+		LogFieldValueInt64s:       "Int64s",
+		LogFieldValueUint64:       "Uint64",
+		LogFieldValueUint64s:      "Uint64s",
+		LogFieldValueFloat64:      "Float64",
+		LogFieldValueFloat64s:     "Floats",
+		LogFieldValueString:       "String",
+		LogFieldValueStrings:      "Strings",
+		LogFieldValueBool:         "Bool",
+		LogFieldValueBools:        "Bools",
+		LogFieldValueTime:         "Time",
+		LogFieldValueDuration:     "Duration",
+		LogFieldValueField:        "Field",
+		LogFieldValueFields:       "Fields",
+		LogFieldValueError:        "Error",
+		LogFieldValueObject:       "Object",
+		LogFieldValueArray:        "Array",
+		LogFieldValueLogValuer:    "LogValuer",
+		LogFieldValueSlice:        "Slice",
+		LogFieldValueMap:          "Map",
+		LogFieldValueNil:          "Nil",
+		LogFieldValueBinary:       "Binary",
+		LogFieldValueByteString:   "ByteString",
+		LogFieldValueIP:           "IP",
+		LogFieldValuePrefix:       "Prefix",
+		LogFieldValueURL:          "URL",
+		LogFieldValueUUID:         "UUID",
+		LogFieldValueHardwareAddr: "HardwareAddr",
 	}
 )
 
+const (
+	// LogValuer 最大解析深度 防止 LogValue 循环返回 LogValuer
+	maxLogValuerDepth = 100
+)
+
+// LogValuer 自定义类型日志输出值
+// LogHandler 在确认日志需要输出后才会调用 LogValue 可用于延迟耗时的计算
+type LogValuer interface {
+	LogValue() LogFieldValue
+}
+
+// lazyValuer 延迟计算的 LogValuer
+type lazyValuer func() any
+
+// LogValue 实现 LogValuer
+func (f lazyValuer) LogValue() LogFieldValue {
+	if f == nil {
+		return AnyFieldValue(nil)
+	}
+	return AnyFieldValue(f())
+}
+
 // String 获取类型对应字符串
 func (l LogFieldValueKind) String() string {
 	if l >= 0 && l < LogFieldValueKind(len(kindStrings)) {
@@ -86,7 +138,7 @@ func Int64FieldValue(val int64) LogFieldValue {
 
 // Int64ArrayFieldValue int64 array
 func Int64ArrayFieldValue(val ...int64) LogFieldValue {
-	return LogFieldValue{kind: LogFieldValueInt64, value: val}
+	return LogFieldValue{kind: LogFieldValueInt64s, value: val}
 }
 
 // Uint64FieldValue uint64
@@ -149,14 +201,239 @@ func FieldArrayFieldValue(val ...LogField) LogFieldValue {
 	return LogFieldValue{kind: LogFieldValueFields, value: val}
 }
 
-// ErrorFieldValue error
+// ErrorFieldValue error 多个错误使用 errors.Join 合并 nil 会被忽略
 func ErrorFieldValue(val ...error) LogFieldValue {
+	if len(val) == 1 {
+		return LogFieldValue{kind: LogFieldValueError, value: val[0]}
+	}
 	return LogFieldValue{kind: LogFieldValueError, value: errors.Join(val...)}
 }
 
+// ObjectFieldValue ObjectMarshaler
+func ObjectFieldValue(val ObjectMarshaler) LogFieldValue {
+	return LogFieldValue{kind: LogFieldValueObject, value: val}
+}
+
+// ArrayFieldValue ArrayMarshaler
+func ArrayFieldValue(val ArrayMarshaler) LogFieldValue {
+	return LogFieldValue{kind: LogFieldValueArray, value: val}
+}
+
+// LogValuerFieldValue LogValuer 输出时解析
+func LogValuerFieldValue(val LogValuer) LogFieldValue {
+	return LogFieldValue{kind: LogFieldValueLogValuer, value: val}
+}
+
+// SliceFieldValue 任意类型切片 输出时才将每个元素按 AnyFieldValue 规则转换
+func SliceFieldValue[T any](val []T) LogFieldValue {
+	if val == nil {
+		return NilFieldValue()
+	}
+	return LogValuerFieldValue(sliceValuer[T](val))
+}
+
+// sliceValuer 延迟转换的切片
+type sliceValuer[T any] []T
+
+// LogValue 实现 LogValuer
+func (s sliceValuer[T]) LogValue() LogFieldValue {
+	values := make([]LogFieldValue, len(s))
+	for idx, elem := range s {
+		values[idx] = AnyFieldValue(elem)
+	}
+	return LogFieldValue{kind: LogFieldValueSlice, value: values}
+}
+
+// MapFieldValue 任意类型 map 输出时才转换
+// 整数 浮点数 字符串 key 按自然顺序排序 其余按字符串形式排序 保证输出顺序稳定
+func MapFieldValue[K comparable, V any](val map[K]V) LogFieldValue {
+	if val == nil {
+		return NilFieldValue()
+	}
+	return LogValuerFieldValue(mapValuer[K, V](val))
+}
+
+// mapValuer 延迟排序与转换的 map
+type mapValuer[K comparable, V any] map[K]V
+
+// LogValue 实现 LogValuer
+func (m mapValuer[K, V]) LogValue() LogFieldValue {
+	type mapEntry struct {
+		key   K
+		value reflect.Value
+		elem  V
+	}
+	entries := make([]mapEntry, 0, len(m))
+	for key, elem := range m {
+		entries = append(entries, mapEntry{key: key, value: reflect.ValueOf(key), elem: elem})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return compareMapKey(entries[i].value, entries[j].value) < 0
+	})
+	fields := make([]LogField, 0, len(m))
+	for _, entry := range entries {
+		fields = append(fields, LogField{Key: fmt.Sprint(entry.key), Value: AnyFieldValue(entry.elem)})
+	}
+	return LogFieldValue{kind: LogFieldValueMap, value: fields}
+}
+
+// map key 排序分类 不同分类按分类顺序排列
+const (
+	mapKeyInt = iota
+	mapKeyUint
+	mapKeyFloat
+	mapKeyString
+	mapKeyOther
+)
+
+// mapKeyClass map key 排序分类
+func mapKeyClass(val reflect.Value) int {
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return mapKeyInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return mapKeyUint
+	case reflect.Float32, reflect.Float64:
+		return mapKeyFloat
+	case reflect.String:
+		return mapKeyString
+	default:
+		return mapKeyOther
+	}
+}
+
+// sprintMapKey map key 字符串形式 nil interface 输出 <nil>
+func sprintMapKey(val reflect.Value) string {
+	if !val.IsValid() {
+		return fmt.Sprint(nil)
+	}
+	return fmt.Sprint(val)
+}
+
+// compareMapKey 整数 浮点数 字符串按自然顺序比较 其余类型按 fmt.Sprint 结果比较
+func compareMapKey(a, b reflect.Value) int {
+	// interface 类型的 key 按动态类型比较
+	if a.Kind() == reflect.Interface && !a.IsNil() {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface && !b.IsNil() {
+		b = b.Elem()
+	}
+	classA, classB := mapKeyClass(a), mapKeyClass(b)
+	if classA != classB {
+		return cmp.Compare(classA, classB)
+	}
+	switch classA {
+	case mapKeyInt:
+		return cmp.Compare(a.Int(), b.Int())
+	case mapKeyUint:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case mapKeyFloat:
+		return cmp.Compare(a.Float(), b.Float())
+	case mapKeyString:
+		return cmp.Compare(a.String(), b.String())
+	default:
+		return cmp.Compare(sprintMapKey(a), sprintMapKey(b))
+	}
+}
+
+// PtrFieldValue 指针 nil 输出空值 否则输出时才按 AnyFieldValue 规则转换指向的值
+func PtrFieldValue[T any](val *T) LogFieldValue {
+	if val == nil {
+		return NilFieldValue()
+	}
+	return LogValuerFieldValue(ptrValuer[T]{ptr: val})
+}
+
+// ptrValuer 延迟解引用的指针
+type ptrValuer[T any] struct {
+	ptr *T
+}
+
+// LogValue 实现 LogValuer
+func (p ptrValuer[T]) LogValue() LogFieldValue {
+	return AnyFieldValue(*p.ptr)
+}
+
+// NilFieldValue 空值
+func NilFieldValue() LogFieldValue {
+	return LogFieldValue{kind: LogFieldValueNil}
+}
+
+// BinaryFieldValue 二进制数据 文本格式输出hex/hexdump Json格式输出base64
+func BinaryFieldValue(val []byte) LogFieldValue {
+	return LogFieldValue{kind: LogFieldValueBinary, value: val}
+}
+
+// ByteStringFieldValue UTF-8 文本字节 按字符串输出
+func ByteStringFieldValue(val []byte) LogFieldValue {
+	return LogFieldValue{kind: LogFieldValueByteString, value: val}
+}
+
+// IPFieldValue IP地址 net.IP 会被转换为 netip.Addr
+func IPFieldValue[T net.IP | netip.Addr](val T) LogFieldValue {
+	var addr netip.Addr
+	switch vv := any(val).(type) {
+	case netip.Addr:
+		addr = vv
+	case net.IP:
+		if vv == nil {
+			return NilFieldValue()
+		}
+		addr, _ = netip.AddrFromSlice(vv)
+		if vv.To4() != nil {
+			addr = addr.Unmap()
+		}
+	}
+	return LogFieldValue{kind: LogFieldValueIP, value: addr}
+}
+
+// PrefixFieldValue CIDR
+func PrefixFieldValue(val netip.Prefix) LogFieldValue {
+	return LogFieldValue{kind: LogFieldValuePrefix, value: val}
+}
+
+// URLFieldValue URL
+func URLFieldValue(val *url.URL) LogFieldValue {
+	if val == nil {
+		return NilFieldValue()
+	}
+	return LogFieldValue{kind: LogFieldValueURL, value: val}
+}
+
+// RedactedURLFieldValue URL 密码会被替换为 xxxxx
+func RedactedURLFieldValue(val *url.URL) LogFieldValue {
+	if val == nil {
+		return NilFieldValue()
+	}
+	if _, ok := val.User.Password(); ok {
+		redacted := *val
+		redacted.User = url.UserPassword(val.User.Username(), "xxxxx")
+		val = &redacted
+	}
+	return LogFieldValue{kind: LogFieldValueURL, value: val}
+}
+
+// UUIDFieldValue 16字节 UUID
+func UUIDFieldValue[T ~[16]byte](val T) LogFieldValue {
+	return LogFieldValue{kind: LogFieldValueUUID, value: [16]byte(val)}
+}
+
+// HardwareAddrFieldValue MAC地址
+func HardwareAddrFieldValue(val net.HardwareAddr) LogFieldValue {
+	if val == nil {
+		return NilFieldValue()
+	}
+	return LogFieldValue{kind: LogFieldValueHardwareAddr, value: val}
+}
+
 // AnyFieldValue any
 func AnyFieldValue(val any) LogFieldValue {
 	switch vv := val.(type) {
+	case nil:
+		return NilFieldValue()
+	case LogFieldValue:
+		return vv
 	case int:
 		return IntFieldValue(vv)
 	case []int:
@@ -184,7 +461,8 @@ func AnyFieldValue(val any) LogFieldValue {
 	case uint8:
 		return Uint64FieldValue(uint64(vv))
 	case []uint8:
-		return Uint64ArrayFieldValue(utils.UintSliceToUint64[uint8](vv)...)
+		// []byte 按二进制数据输出
+		return BinaryFieldValue(vv)
 	case uint16:
 		return Uint64FieldValue(uint64(vv))
 	case []uint16:
@@ -215,12 +493,40 @@ func AnyFieldValue(val any) LogFieldValue {
 		return BoolArrayFieldValue(vv...)
 	case time.Time:
 		return TimeFieldValue(vv)
+	case []time.Time:
+		return SliceFieldValue(vv)
 	case time.Duration:
 		return DurationFieldValue(vv)
+	case []time.Duration:
+		return SliceFieldValue(vv)
+	case []error:
+		return SliceFieldValue(vv)
+	case []any:
+		return SliceFieldValue(vv)
+	case map[string]any:
+		return MapFieldValue(vv)
+	case map[string]string:
+		return MapFieldValue(vv)
+	case net.IP:
+		return IPFieldValue(vv)
+	case netip.Addr:
+		return IPFieldValue(vv)
+	case netip.Prefix:
+		return PrefixFieldValue(vv)
+	case *url.URL:
+		return URLFieldValue(vv)
+	case net.HardwareAddr:
+		return HardwareAddrFieldValue(vv)
 	case LogField:
 		return FieldFieldValue(vv)
 	case []LogField:
 		return FieldArrayFieldValue(vv...)
+	case LogValuer:
+		return LogValuerFieldValue(vv)
+	case ObjectMarshaler:
+		return ObjectFieldValue(vv)
+	case ArrayMarshaler:
+		return ArrayFieldValue(vv)
 	case error:
 		return ErrorFieldValue(vv)
 	case fmt.Stringer:
@@ -284,8 +590,11 @@ func (l LogFieldValue) String() string {
 		return l.value.(string)
 	}
 
-	buffer := make([]byte, 0)
-	return string(l.appendFieldValue(buffer))
+	buffer := bufferPool.Get()
+	defer buffer.Free()
+
+	_ = newTextEncoder(buffer, nil).appendValue(l)
+	return buffer.String()
 }
 
 func (l LogFieldValue) Strings() []string {
@@ -341,7 +650,120 @@ func (l LogFieldValue) Error() error {
 	if current, target := l.Kind(), LogFieldValueError; current != target {
 		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
 	}
-	return l.value.(error)
+	// 允许 nil error
+	err, _ := l.value.(error)
+	return err
+}
+
+func (l LogFieldValue) Object() ObjectMarshaler {
+	if current, target := l.Kind(), LogFieldValueObject; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	// nil ObjectMarshaler 由编码器输出空值
+	val, _ := l.value.(ObjectMarshaler)
+	return val
+}
+
+func (l LogFieldValue) Array() ArrayMarshaler {
+	if current, target := l.Kind(), LogFieldValueArray; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	// nil ArrayMarshaler 由编码器输出空值
+	val, _ := l.value.(ArrayMarshaler)
+	return val
+}
+
+func (l LogFieldValue) LogValuer() LogValuer {
+	if current, target := l.Kind(), LogFieldValueLogValuer; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	return l.value.(LogValuer)
+}
+
+// Resolve 解析 LogValuer 直到得到非 LogValuer 的值
+// 超出最大解析深度或者 LogValue 发生 panic 时返回 Error 类型的值
+func (l LogFieldValue) Resolve() LogFieldValue {
+	origin := l
+	for depth := 0; l.Kind() == LogFieldValueLogValuer; depth++ {
+		if depth == maxLogValuerDepth {
+			return ErrorFieldValue(fmt.Errorf("LogValuer %T exceeded max resolve depth %d", origin.value, maxLogValuerDepth))
+		}
+		l = resolveLogValuer(l.LogValuer())
+	}
+	return l
+}
+
+// resolveLogValuer 调用 LogValue 捕获 panic
+func resolveLogValuer(valuer LogValuer) (val LogFieldValue) {
+	defer func() {
+		if r := recover(); r != nil {
+			val = ErrorFieldValue(fmt.Errorf("LogValuer %T panicked: %v", valuer, r))
+		}
+	}()
+	return valuer.LogValue()
+}
+
+func (l LogFieldValue) Slice() []LogFieldValue {
+	if current, target := l.Kind(), LogFieldValueSlice; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	return l.value.([]LogFieldValue)
+}
+
+func (l LogFieldValue) Map() []LogField {
+	if current, target := l.Kind(), LogFieldValueMap; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	return l.value.([]LogField)
+}
+
+func (l LogFieldValue) Binary() []byte {
+	if current, target := l.Kind(), LogFieldValueBinary; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	return l.value.([]byte)
+}
+
+func (l LogFieldValue) ByteString() []byte {
+	if current, target := l.Kind(), LogFieldValueByteString; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	return l.value.([]byte)
+}
+
+func (l LogFieldValue) IP() netip.Addr {
+	if current, target := l.Kind(), LogFieldValueIP; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	return l.value.(netip.Addr)
+}
+
+func (l LogFieldValue) Prefix() netip.Prefix {
+	if current, target := l.Kind(), LogFieldValuePrefix; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	return l.value.(netip.Prefix)
+}
+
+func (l LogFieldValue) URL() *url.URL {
+	if current, target := l.Kind(), LogFieldValueURL; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	return l.value.(*url.URL)
+}
+
+func (l LogFieldValue) UUID() [16]byte {
+	if current, target := l.Kind(), LogFieldValueUUID; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	return l.value.([16]byte)
+}
+
+func (l LogFieldValue) HardwareAddr() net.HardwareAddr {
+	if current, target := l.Kind(), LogFieldValueHardwareAddr; current != target {
+		panic(fmt.Sprintf("current FieldValueKind is %s, not %s", kindStrings[current], kindStrings[target]))
+	}
+	return l.value.(net.HardwareAddr)
 }
 
 func (l LogFieldValue) Any() any {
@@ -378,176 +800,33 @@ func (l LogFieldValue) Any() any {
 		return l.Fields()
 	case LogFieldValueError:
 		return l.Error()
+	case LogFieldValueObject:
+		return l.Object()
+	case LogFieldValueArray:
+		return l.Array()
+	case LogFieldValueLogValuer:
+		return l.LogValuer()
+	case LogFieldValueSlice:
+		return l.Slice()
+	case LogFieldValueMap:
+		return l.Map()
+	case LogFieldValueNil:
+		return nil
+	case LogFieldValueBinary:
+		return l.Binary()
+	case LogFieldValueByteString:
+		return l.ByteString()
+	case LogFieldValueIP:
+		return l.IP()
+	case LogFieldValuePrefix:
+		return l.Prefix()
+	case LogFieldValueURL:
+		return l.URL()
+	case LogFieldValueUUID:
+		return l.UUID()
+	case LogFieldValueHardwareAddr:
+		return l.HardwareAddr()
 	default:
 		panic(fmt.Sprintf("unknown kind %s", l.Kind()))
 	}
 }
-
-func (l LogFieldValue) appendFieldValue(dst []byte) []byte {
-	switch l.Kind() {
-	case LogFieldValueInt64:
-		return strconv.AppendInt(dst, l.value.(int64), 10)
-	case LogFieldValueUint64:
-		return strconv.AppendUint(dst, l.value.(uint64), 10)
-	case LogFieldValueFloat64:
-		return strconv.AppendFloat(dst, l.value.(float64), 'f', -1, 64)
-	case LogFieldValueBool:
-		return strconv.AppendBool(dst, l.value.(bool))
-	case LogFieldValueTime:
-		return l.value.(time.Time).AppendFormat(dst, DefaultTimeLayout)
-	case LogFieldValueDuration:
-		return append(dst, l.value.(time.Duration).String()...)
-	case LogFieldValueString:
-		return append(dst, l.value.(string)...)
-	case LogFieldValueError:
-		return append(dst, fmt.Sprintf("err: %s", l.value.(error).Error())...)
-	case LogFieldValueAny:
-		return fmt.Append(dst, l.value)
-	case LogFieldValueField:
-		return fmt.Append(dst, l.value)
-	case LogFieldValueFields:
-		return append(dst, l.serializeFields()...)
-	case LogFieldValueInt64s:
-		return append(dst, l.serializeInt64s()...)
-	case LogFieldValueUint64s:
-		return append(dst, l.serializeUint64s()...)
-	case LogFieldValueFloat64s:
-		return append(dst, l.serializeFloat64s()...)
-	case LogFieldValueStrings:
-		return append(dst, l.serializeStrings()...)
-	case LogFieldValueBools:
-		return append(dst, l.serializeBools()...)
-	default:
-		panic(fmt.Sprintf("Invalid FieldValueKind %s", kindStrings[l.Kind()]))
-	}
-	return dst
-}
-
-func (l LogFieldValue) serializeFields() []byte {
-	var buffer []byte
-	fields, ok := l.value.([]LogField)
-	if !ok {
-		return buffer
-	}
-	buffer = append(buffer, serializeArrayBegin)
-	for idx, field := range fields {
-		if idx > 0 {
-			buffer = append(buffer, serializeCommaStep, serializeSpaceSplit)
-		}
-		data, err := field.MarshalText()
-		if err != nil {
-			buffer = fmt.Append(buffer, field)
-			continue
-		}
-		buffer = append(buffer, data...)
-	}
-	return append(buffer, serializeArrayEnd)
-}
-
-func (l LogFieldValue) serializeInt64s() []byte {
-	buffer := bufferPool.Get()
-	defer buffer.Free()
-
-	nums, ok := l.value.([]int64)
-	if !ok {
-		return buffer.Bytes()
-	}
-	buffer.AppendByte(serializeArrayBegin)
-	for idx, num := range nums {
-		if idx > 0 {
-			buffer.AppendByte(serializeCommaStep)
-			buffer.AppendByte(serializeSpaceSplit)
-		}
-		buffer.AppendInt(num)
-	}
-	buffer.AppendByte(serializeArrayEnd)
-
-	return buffer.Bytes()
-}
-
-func (l LogFieldValue) serializeUint64s() []byte {
-	buffer := bufferPool.Get()
-	defer buffer.Free()
-
-	nums, ok := l.value.([]uint64)
-	if !ok {
-		return buffer.Bytes()
-	}
-
-	buffer.AppendByte(serializeArrayBegin)
-	for idx, num := range nums {
-		if idx > 0 {
-			buffer.AppendByte(serializeCommaStep)
-			buffer.AppendByte(serializeSpaceSplit)
-		}
-		buffer.AppendUint(num)
-	}
-	buffer.AppendByte(serializeArrayEnd)
-
-	return buffer.Bytes()
-}
-
-func (l LogFieldValue) serializeFloat64s() []byte {
-	buffer := bufferPool.Get()
-	defer buffer.Free()
-
-	nums, ok := l.value.([]float64)
-	if !ok {
-		return buffer.Bytes()
-	}
-
-	buffer.AppendByte(serializeArrayBegin)
-	for idx, num := range nums {
-		if idx > 0 {
-			buffer.AppendByte(serializeCommaStep)
-			buffer.AppendByte(serializeSpaceSplit)
-		}
-		buffer.AppendFloat(num, 64)
-	}
-	buffer.AppendByte(serializeArrayEnd)
-
-	return buffer.Bytes()
-}
-
-// string 可以使用 strings.Builder 构建
-func (l LogFieldValue) serializeStrings() []byte {
-	var builder strings.Builder
-	strs, ok := l.value.([]string)
-	if !ok {
-		return nil
-	}
-
-	builder.WriteByte(serializeArrayBegin)
-	for idx, str := range strs {
-		if idx > 0 {
-			builder.WriteByte(serializeCommaStep)
-			builder.WriteByte(serializeSpaceSplit)
-		}
-		builder.WriteString(str)
-	}
-	builder.WriteByte(serializeArrayEnd)
-
-	return []byte(builder.String())
-}
-
-func (l LogFieldValue) serializeBools() []byte {
-	buffer := bufferPool.Get()
-	defer buffer.Free()
-
-	bools, ok := l.value.([]bool)
-	if !ok {
-		return nil
-	}
-
-	buffer.AppendByte(serializeArrayBegin)
-	for idx, boolVal := range bools {
-		if idx > 0 {
-			buffer.AppendByte(serializeCommaStep)
-			buffer.AppendByte(serializeSpaceSplit)
-		}
-		buffer.AppendBool(boolVal)
-	}
-	buffer.AppendByte(serializeArrayEnd)
-
-	return buffer.Bytes()
-}
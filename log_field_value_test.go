@@ -0,0 +1,188 @@
+package gslog
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// mapKeys MapFieldValue 输出的 key 顺序
+func mapKeys(val LogFieldValue) []string {
+	var keys []string
+	for _, field := range val.Resolve().Map() {
+		keys = append(keys, field.Key)
+	}
+	return keys
+}
+
+type testMapKey struct {
+	name string
+}
+
+func (k testMapKey) String() string {
+	return k.name
+}
+
+func TestMapFieldValueOrder(t *testing.T) {
+	type named int
+
+	tests := []struct {
+		name string
+		val  LogFieldValue
+		want []string
+	}{
+		{"int", MapFieldValue(map[int]bool{10: true, 9: true, -1: true, 100: true}), []string{"-1", "9", "10", "100"}},
+		{"named int", MapFieldValue(map[named]bool{10: true, 2: true}), []string{"2", "10"}},
+		{"uint", MapFieldValue(map[uint8]bool{20: true, 3: true}), []string{"3", "20"}},
+		{"float", MapFieldValue(map[float64]bool{10.5: true, 9.25: true, -2: true}), []string{"-2", "9.25", "10.5"}},
+		{"float nan", MapFieldValue(map[float64]bool{math.NaN(): true, 1: true}), []string{"NaN", "1"}},
+		{"string", MapFieldValue(map[string]int{"b": 1, "a": 2, "B": 3}), []string{"B", "a", "b"}},
+		{"any", MapFieldValue(map[any]int{"x": 1, 10: 2, 9: 3, nil: 4}), []string{"9", "10", "x", "<nil>"}},
+		{"stringer", MapFieldValue(map[testMapKey]int{{"b"}: 1, {"a"}: 2}), []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapKeys(tt.val)
+			if len(got) != len(tt.want) {
+				t.Fatalf("keys = %v, want %v", got, tt.want)
+			}
+			for idx := range got {
+				if got[idx] != tt.want[idx] {
+					t.Fatalf("keys = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// countingStringer 记录 String 调用次数
+type countingStringer struct {
+	calls *int
+}
+
+func (c countingStringer) String() string {
+	*c.calls++
+	return "s"
+}
+
+func TestLazyFieldValueConversion(t *testing.T) {
+	for name, newHandler := range map[string]func(writer WriteSyncer) LogHandler{
+		"text": newDefaultTextHandler,
+		"json": newDefaultJsonHandler,
+	} {
+		t.Run(name, func(t *testing.T) {
+			writer := &bufferWriteSyncer{}
+			logger := NewLogger(newHandler(writer))
+
+			// 未输出的日志不会转换元素
+			calls := 0
+			elems := []countingStringer{{&calls}, {&calls}}
+			logger.Debug("skipped", Slice("slice", elems), Map("map", map[string]countingStringer{"a": {&calls}}), Ptr("ptr", &elems[0]))
+			if calls != 0 || writer.String() != "" {
+				t.Fatalf("disabled entry converted %d values, output %q", calls, writer.String())
+			}
+
+			logger.Info("logged", Slice("slice", elems), Map("map", map[string]countingStringer{"a": {&calls}}), Ptr("ptr", &elems[0]))
+			if calls != 4 {
+				t.Errorf("enabled entry converted %d values, want 4", calls)
+			}
+
+			// 输出时才读取指针指向的值
+			num := 1
+			field := Ptr("num", &num)
+			num = 2
+			ptrWriter := &bufferWriteSyncer{}
+			NewLogger(newHandler(ptrWriter)).Info("ptr", field)
+			if want := map[string]string{"text": "num=2", "json": `"num":2`}[name]; !strings.Contains(ptrWriter.String(), want) {
+				t.Errorf("output %q does not contain %q", ptrWriter.String(), want)
+			}
+
+			if kind := field.Value.Resolve().Kind(); kind != LogFieldValueInt64 {
+				t.Errorf("resolved kind = %s, want Int64", kind)
+			}
+			if kind := SliceFieldValue([]int8{1}).Resolve().Kind(); kind != LogFieldValueSlice {
+				t.Errorf("resolved slice kind = %s, want Slice", kind)
+			}
+			if kind := MapFieldValue(map[int]int{1: 1}).Resolve().Kind(); kind != LogFieldValueMap {
+				t.Errorf("resolved map kind = %s, want Map", kind)
+			}
+		})
+	}
+}
+
+// testToken LogValuer 隐藏敏感字段
+type testToken struct {
+	id     int
+	secret string
+}
+
+func (t testToken) LogValue() LogFieldValue {
+	return FieldArrayFieldValue(Int("id", t.id), String("secret", "***"))
+}
+
+// testChainValuer LogValue 返回下一层 LogValuer 剩余层数为 0 时返回字符串
+type testChainValuer int
+
+func (c testChainValuer) LogValue() LogFieldValue {
+	if c == 0 {
+		return StringFieldValue("done")
+	}
+	return LogValuerFieldValue(c - 1)
+}
+
+// testPanicValuer LogValue 发生 panic
+type testPanicValuer struct{}
+
+func (testPanicValuer) LogValue() LogFieldValue {
+	panic("boom")
+}
+
+func TestLogValuerResolve(t *testing.T) {
+	for name, newHandler := range map[string]func(writer WriteSyncer) LogHandler{
+		"text": func(writer WriteSyncer) LogHandler {
+			return NewTextHandlerWithOptions(writer, WithTextFlag(LTextLogLevel))
+		},
+		"json": newDefaultJsonHandler,
+	} {
+		t.Run(name, func(t *testing.T) {
+			writer := &bufferWriteSyncer{}
+			logger := NewLogger(newHandler(writer))
+
+			calls := 0
+			lazy := Lazy("lazy", func() any {
+				calls++
+				return []string{"a", "b"}
+			})
+			logger.Debug("skipped", lazy)
+			if calls != 0 {
+				t.Errorf("Lazy called %d times for disabled entry", calls)
+			}
+			logger.Info("msg",
+				lazy,
+				Any("token", testToken{id: 1, secret: "s3cr3t"}),
+				Any("chain", testChainValuer(maxLogValuerDepth-1)),
+				Any("loop", testChainValuer(maxLogValuerDepth)),
+				Any("panic", testPanicValuer{}),
+				Lazy("nilFunc", nil),
+				Slice("nested", []any{testToken{id: 2}, testChainValuer(1)}),
+			)
+			if calls != 1 {
+				t.Errorf("Lazy called %d times, want 1", calls)
+			}
+
+			// 最多解析 maxLogValuerDepth 层 panic 转换为错误
+			depthErr := fmt.Sprintf("LogValuer gslog.testChainValuer exceeded max resolve depth %d", maxLogValuerDepth)
+			panicErr := "LogValuer gslog.testPanicValuer panicked: boom"
+			want := map[string]string{
+				"text": "[Info] msg lazy=[a, b] token=[id=1, secret=***] chain=done loop=err: " + depthErr + " panic=err: " + panicErr +
+					" nilFunc=<nil> nested=[[id=2, secret=***], done] \n",
+				"json": `"fields":[{"lazy":["a","b"]},{"token":[{"id":1},{"secret":"***"}]},{"chain":"done"},{"loop":{"message":"` + depthErr +
+					`"}},{"panic":{"message":"` + panicErr + `"}},{"nilFunc":null},{"nested":[[{"id":2},{"secret":"***"}],"done"]}]}` + "\n",
+			}[name]
+			if got := writer.Lines(); len(got) != 1 || !strings.HasSuffix(writer.String(), want) {
+				t.Errorf("output\n got %s\nwant ...%s", writer.String(), want)
+			}
+		})
+	}
+}
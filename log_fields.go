@@ -1,8 +1,12 @@
 package gslog
 
 import (
+	"bytes"
 	"encoding"
 	"encoding/json"
+	"net"
+	"net/netip"
+	"net/url"
 	"time"
 
 	"gslog/internal/bufferPool"
@@ -62,9 +66,23 @@ func Errors(key string, val ...error) LogField {
 	}
 }
 
+// Err 使用统一的 key "error" 写入错误
+func Err(err error) LogField {
+	return NamedError(errorFieldKey, err)
+}
+
+// NamedError 使用指定 key 写入错误
+func NamedError(key string, err error) LogField {
+	return LogField{
+		Key:   key,
+		Value: ErrorFieldValue(err),
+	}
+}
+
+// Fields 字段组 单个字段输出为嵌套字段 无字段时输出空字段组
 func Fields(key string, val ...LogField) LogField {
 	fields := LogField{Key: key}
-	if len(val) <= 1 {
+	if len(val) == 1 {
 		fields.Value = FieldFieldValue(val[0])
 		return fields
 	}
@@ -86,6 +104,118 @@ func Duration(key string, val time.Duration) LogField {
 	}
 }
 
+// Object 以 ObjectMarshaler 写入对象 无需反射
+func Object(key string, val ObjectMarshaler) LogField {
+	return LogField{
+		Key:   key,
+		Value: ObjectFieldValue(val),
+	}
+}
+
+// Array 以 ArrayMarshaler 写入数组 无需反射
+func Array(key string, val ArrayMarshaler) LogField {
+	return LogField{
+		Key:   key,
+		Value: ArrayFieldValue(val),
+	}
+}
+
+// Slice 任意类型切片 输出时元素才按 AnyFieldValue 规则原生输出
+func Slice[T any](key string, val []T) LogField {
+	return LogField{
+		Key:   key,
+		Value: SliceFieldValue(val),
+	}
+}
+
+// Map 任意类型 map 输出时才按 key 排序 保证输出顺序稳定
+func Map[K comparable, V any](key string, val map[K]V) LogField {
+	return LogField{
+		Key:   key,
+		Value: MapFieldValue(val),
+	}
+}
+
+// Ptr 指针 nil 安全输出 输出时才解引用
+func Ptr[T any](key string, val *T) LogField {
+	return LogField{
+		Key:   key,
+		Value: PtrFieldValue(val),
+	}
+}
+
+// Binary 二进制数据 文本格式按 LogOptions.BinaryEncoding 输出hex/hexdump Json格式输出base64
+func Binary(key string, val []byte) LogField {
+	return LogField{
+		Key:   key,
+		Value: BinaryFieldValue(val),
+	}
+}
+
+// ByteString UTF-8 文本字节 按字符串输出 避免转换为 string
+func ByteString(key string, val []byte) LogField {
+	return LogField{
+		Key:   key,
+		Value: ByteStringFieldValue(val),
+	}
+}
+
+// IP IP地址 支持 net.IP 以及 netip.Addr
+func IP[T net.IP | netip.Addr](key string, val T) LogField {
+	return LogField{
+		Key:   key,
+		Value: IPFieldValue(val),
+	}
+}
+
+// Prefix CIDR
+func Prefix(key string, val netip.Prefix) LogField {
+	return LogField{
+		Key:   key,
+		Value: PrefixFieldValue(val),
+	}
+}
+
+// URL 完整输出URL
+func URL(key string, val *url.URL) LogField {
+	return LogField{
+		Key:   key,
+		Value: URLFieldValue(val),
+	}
+}
+
+// RedactedURL 输出URL 隐藏密码
+func RedactedURL(key string, val *url.URL) LogField {
+	return LogField{
+		Key:   key,
+		Value: RedactedURLFieldValue(val),
+	}
+}
+
+// UUID 16字节 UUID 输出标准 8-4-4-4-12 格式
+func UUID[T ~[16]byte](key string, val T) LogField {
+	return LogField{
+		Key:   key,
+		Value: UUIDFieldValue(val),
+	}
+}
+
+// HardwareAddr MAC地址
+func HardwareAddr(key string, val net.HardwareAddr) LogField {
+	return LogField{
+		Key:   key,
+		Value: HardwareAddrFieldValue(val),
+	}
+}
+
+// Lazy 延迟计算字段值 仅在日志确认输出时调用 fn
+func Lazy(key string, fn func() any) LogField {
+	return LogField{
+		Key:   key,
+		Value: LogValuerFieldValue(lazyValuer(fn)),
+	}
+}
+
 func Any(key string, val any) LogField {
 	return LogField{
 		Key:   key,
@@ -99,33 +229,9 @@ func (l LogField) MarshalText() ([]byte, error) {
 	buffer := bufferPool.Get()
 	defer buffer.Free()
 
-	buffer.AppendString(l.Key)
-	if l.Value.Kind() == LogFieldValueField {
-		// key.k=v
-		buffer.AppendByte('.')
-		data, err := l.Value.Field().MarshalText()
-		if err != nil {
-			return nil, err
-		}
-		buffer.AppendBytes(data)
-		return buffer.Bytes(), nil
-	}
-	buffer.AppendByte('=')
-	if l.Value.Kind() == LogFieldValueAny {
-		// 值是 any 类型调用 尝试调用 encoding.TextMarshaler
-		if vv, ok := l.Value.Any().(encoding.TextMarshaler); ok {
-			data, err := vv.MarshalText()
-			if err != nil {
-				return nil, err
-			}
-			buffer.AppendBytes(data)
-			return buffer.Bytes(), nil
-		}
-	}
-	// default
-	buffer.AppendString(l.Value.String())
-
-	return buffer.Bytes(), nil
+	newTextEncoder(buffer, nil).appendField(l)
+
+	return bytes.Clone(buffer.Bytes()), nil
 }
 
 // MarshalJSON 实现 json.Marshaler
@@ -134,16 +240,7 @@ func (l LogField) MarshalJSON() ([]byte, error) {
 	buffer := bufferPool.Get()
 	defer buffer.Free()
 
-	// TODO 这里是否需要对空指针处理...
-
-	allFieldKV := make(map[string]any)
-	allFieldKV[l.Key] = l.Value.Any()
-
-	data, err := json.Marshal(allFieldKV)
-	if err != nil {
-		return nil, err
-	}
-	buffer.AppendBytes(data)
+	newJsonEncoder(buffer, nil).appendField(l)
 
-	return buffer.Bytes(), nil
+	return bytes.Clone(buffer.Bytes()), nil
 }
@@ -0,0 +1,240 @@
+package gslog
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	// 检查 logFileReader 实现 io.ReadCloser
+	_ io.ReadCloser = (*logFileReader)(nil)
+)
+
+// OpenReader 按时间顺序读取备份日志(自动解压 .gz)以及当前日志文件中 [since, until] 区间内的日志行
+// since/until 为零值时表示不限制 备份文件名中的时间为轮转时间 据此跳过不在区间内的文件
+// opts 应与写入日志的 Handler 配置一致 日志行时间按其 Layout/TimeEncoder/前缀/encode key 解析
+// 设置了时间区间时无法解析时间的行(例如调用栈 panic 输出)视为上一行的延续 随上一行保留或跳过
+// 当前日志文件在打开时即持有 读取过程中发生轮转不影响结果
+func (l *LogFileRollover) OpenReader(since, until time.Time, opts ...Options) (io.ReadCloser, error) {
+	logFileMetas, err := l.loadFileList()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	reader := &logFileReader{
+		since: since,
+		until: until,
+		text:  NewTextDecoderWithOptions(opts...),
+		json:  NewJsonDecoderWithOptions(opts...),
+	}
+
+	// 时间从小到大 压缩中的文件同时存在原文件以及 .gz 文件 优先读取原文件
+	var previous time.Time
+	for idx := len(logFileMetas) - 1; idx >= 0; idx-- {
+		logFileMeta := logFileMetas[idx]
+		if idx > 0 && logFileMetas[idx-1].Time.Equal(logFileMeta.Time) {
+			if strings.HasSuffix(logFileMeta.FileInfo.Name(), compressSuffix) {
+				continue
+			}
+			logFileMetas[idx-1] = logFileMeta
+			continue
+		}
+		// 备份内的日志时间位于 (previous, Time]
+		if !since.IsZero() && logFileMeta.Time.Before(since) {
+			previous = logFileMeta.Time
+			continue
+		}
+		if !until.IsZero() && previous.After(until) {
+			break
+		}
+		reader.backups = append(reader.backups, filepath.Join(l.filePath(), logFileMeta.FileInfo.Name()))
+		previous = logFileMeta.Time
+	}
+
+	// 当前文件
+	if until.IsZero() || !previous.After(until) {
+		file, err := os.Open(l.filename())
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		reader.current = file
+	}
+
+	return reader, nil
+}
+
+// logFileReader 依次读取多个日志文件
+type logFileReader struct {
+	since, until time.Time
+	// 待读取备份文件
+	backups []string
+	// 当前日志文件
+	current *os.File
+	// 正在读取的文件
+	file    *os.File
+	gz      *gzip.Reader
+	scanner *bufio.Reader
+	// 未读取完的日志行
+	pending []byte
+	// 上一个可解析时间的行是否位于区间内 无法解析时间的行随其保留或跳过
+	continued bool
+	// 解析日志行时间
+	text *TextDecoder
+	json *JsonDecoder
+}
+
+// Read 实现 io.Reader
+func (r *logFileReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		line, err := r.readLine()
+		if err != nil {
+			return 0, err
+		}
+		if r.accept(line) {
+			r.pending = line
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// Close 实现 io.Closer
+func (r *logFileReader) Close() error {
+	err := r.closeFile()
+	if r.current != nil {
+		err = errors.Join(err, r.current.Close())
+		r.current = nil
+	}
+	r.backups = nil
+	return err
+}
+
+// readLine 读取下一行 当前文件读取完毕后切换到下一个文件
+func (r *logFileReader) readLine() ([]byte, error) {
+	for {
+		if r.scanner == nil {
+			if err := r.openNext(); err != nil {
+				return nil, err
+			}
+		}
+		line, err := r.scanner.ReadBytes(serializeNewLine)
+		if len(line) > 0 {
+			// 文件末尾未写完整的行补充换行
+			if line[len(line)-1] != serializeNewLine {
+				line = append(line, serializeNewLine)
+			}
+			return line, nil
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if err = r.closeFile(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// openNext 打开下一个文件 所有文件读取完毕返回 io.EOF
+func (r *logFileReader) openNext() error {
+	for len(r.backups) > 0 {
+		name := r.backups[0]
+		r.backups = r.backups[1:]
+
+		file, err := os.Open(name)
+		if os.IsNotExist(err) && !strings.HasSuffix(name, compressSuffix) {
+			// 读取过程中被压缩
+			name += compressSuffix
+			file, err = os.Open(name)
+		}
+		if os.IsNotExist(err) {
+			// 读取过程中被清理
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		r.file = file
+		if !strings.HasSuffix(name, compressSuffix) {
+			r.scanner = bufio.NewReader(file)
+			return nil
+		}
+		if r.gz, err = gzip.NewReader(file); err != nil {
+			_ = r.closeFile()
+			return err
+		}
+		r.scanner = bufio.NewReader(r.gz)
+		return nil
+	}
+
+	if r.current == nil {
+		return io.EOF
+	}
+	r.file, r.current = r.current, nil
+	r.scanner = bufio.NewReader(r.file)
+	return nil
+}
+
+// closeFile 关闭正在读取的文件
+func (r *logFileReader) closeFile() error {
+	var err error
+	if r.gz != nil {
+		err = r.gz.Close()
+		r.gz = nil
+	}
+	if r.file != nil {
+		err = errors.Join(err, r.file.Close())
+		r.file = nil
+	}
+	r.scanner = nil
+	return err
+}
+
+// accept 日志行时间是否位于区间内 无法解析时间的行视为上一行的延续
+func (r *logFileReader) accept(line []byte) bool {
+	if r.since.IsZero() && r.until.IsZero() {
+		return true
+	}
+	tm, ok := r.lineTime(line)
+	if !ok {
+		// 调用栈等多行输出 跟随上一行
+		return r.continued
+	}
+	r.continued = (r.since.IsZero() || !tm.Before(r.since)) && (r.until.IsZero() || !tm.After(r.until))
+	return r.continued
+}
+
+// lineTime 解析日志行时间 文本格式跳过 <prefix> 前缀 未配置前缀时跳过任意 <...> 前缀
+func (r *logFileReader) lineTime(line []byte) (time.Time, bool) {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) > 0 && trimmed[0] == serializeJsonStart {
+		entry, err := r.json.Decode(trimmed)
+		if err != nil || entry.Time.IsZero() {
+			return time.Time{}, false
+		}
+		return entry.Time, true
+	}
+
+	rest := string(trimmed) + string(serializeSpaceSplit)
+	if prefix := r.text.options.TextPrefix; prefix != "" {
+		head := string(serializePrefixBegin) + prefix + string(serializePrefixEnd) + string(serializeSpaceSplit)
+		if !strings.HasPrefix(rest, head) {
+			return time.Time{}, false
+		}
+		rest = rest[len(head):]
+	} else if strings.HasPrefix(rest, string(serializePrefixBegin)) {
+		if idx := strings.Index(rest, string(serializePrefixEnd)+string(serializeSpaceSplit)); idx > 0 {
+			rest = rest[idx+2:]
+		}
+	}
+	entry := &DecodedEntry{}
+	r.text.decodeTime(entry, rest)
+	return entry.Time, !entry.Time.IsZero()
+}
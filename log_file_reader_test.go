@@ -0,0 +1,190 @@
+package gslog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gslog/internal/utils"
+)
+
+// writeLogLines 使用 handler 输出指定时间的日志 消息为 msgs
+func writeLogLines(t *testing.T, newHandler func(writer WriteSyncer) LogHandler, start time.Time, msgs ...string) []byte {
+	t.Helper()
+
+	writer := &bufferWriteSyncer{}
+	handler := newHandler(writer)
+	for idx, msg := range msgs {
+		entry := NewLogEntry(start.Add(time.Duration(idx)*time.Minute), InfoLevel, msg, 0)
+		if err := handler.LogRecord(context.Background(), entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return []byte(writer.String())
+}
+
+// writeRolloverFiles 创建 gzip 备份 普通备份以及当前文件 每个文件中日志间隔 1 分钟
+func writeRolloverFiles(t *testing.T, name string, base time.Time, newHandler func(writer WriteSyncer) LogHandler) {
+	t.Helper()
+
+	var gz bytes.Buffer
+	gzWriter := gzip.NewWriter(&gz)
+	_, _ = gzWriter.Write(writeLogLines(t, newHandler, base, "gz-1", "gz-2"))
+	_ = gzWriter.Close()
+
+	files := map[string][]byte{
+		utils.GetBackupNameByTime(name, backupTimeFormat, base.Add(time.Hour)) + compressSuffix: gz.Bytes(),
+		utils.GetBackupNameByTime(name, backupTimeFormat, base.Add(3*time.Hour)):                writeLogLines(t, newHandler, base.Add(2*time.Hour), "plain-1", "plain-2"),
+		name: append(writeLogLines(t, newHandler, base.Add(4*time.Hour), "current-1", "current-2"), "not a log line\n"...),
+	}
+	for file, data := range files {
+		if err := os.WriteFile(file, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readMessages 使用 decoder 解析 OpenReader 读取的全部行 无法解析的行记为原始内容
+func readMessages(t *testing.T, reader io.ReadCloser, decoder Decoder) []string {
+	t.Helper()
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		if entry, err := decoder.Decode([]byte(line)); err == nil {
+			msgs = append(msgs, entry.Msg)
+		} else {
+			msgs = append(msgs, line)
+		}
+	}
+	return msgs
+}
+
+func TestLogFileRolloverOpenReaderOptions(t *testing.T) {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local)
+	formats := []struct {
+		name    string
+		opts    []Options
+		handler func(writer WriteSyncer, opts ...Options) LogHandler
+		decoder func(opts ...Options) Decoder
+	}{
+		{
+			name: "text",
+			opts: []Options{WithPrefix("app"), WithTextFlag(LTextTime | LTextLogLevel), WithLayout(time.RFC3339)},
+			handler: func(writer WriteSyncer, opts ...Options) LogHandler {
+				return NewTextHandlerWithOptions(writer, opts...)
+			},
+			decoder: func(opts ...Options) Decoder { return NewTextDecoderWithOptions(opts...) },
+		},
+		{
+			name: "json",
+			opts: []Options{WithTimeEncodeKey("ts"), WithTimeEncoder(UnixMilliTimeEncoder)},
+			handler: func(writer WriteSyncer, opts ...Options) LogHandler {
+				return NewJsonHandlerWithOptions(writer, opts...)
+			},
+			decoder: func(opts ...Options) Decoder { return NewJsonDecoderWithOptions(opts...) },
+		},
+	}
+
+	for _, format := range formats {
+		t.Run(format.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "app.log")
+			writeRolloverFiles(t, name, base, func(writer WriteSyncer) LogHandler {
+				return format.handler(writer, format.opts...)
+			})
+			rollover := NewLogFileRollover(name, 1, 0, 0, false)
+			decoder := format.decoder(format.opts...)
+
+			tests := []struct {
+				name         string
+				since, until time.Time
+				want         []string
+			}{
+				{"all", time.Time{}, time.Time{}, []string{"gz-1", "gz-2", "plain-1", "plain-2", "current-1", "current-2", "not a log line"}},
+				{"since", base.Add(time.Minute), time.Time{}, []string{"gz-2", "plain-1", "plain-2", "current-1", "current-2", "not a log line"}},
+				{"until", time.Time{}, base.Add(2*time.Hour + 30*time.Second), []string{"gz-1", "gz-2", "plain-1"}},
+				{"window", base.Add(2*time.Hour + time.Minute), base.Add(4 * time.Hour), []string{"plain-2", "current-1"}},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					reader, err := rollover.OpenReader(tt.since, tt.until, format.opts...)
+					if err != nil {
+						t.Fatal(err)
+					}
+					got := readMessages(t, reader, decoder)
+					if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+						t.Errorf("messages = %q, want %q", got, tt.want)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestLogFileRolloverOpenReaderMismatchedOptions(t *testing.T) {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local)
+	name := filepath.Join(t.TempDir(), "app.log")
+	opts := []Options{WithPrefix("app"), WithTextFlag(LTextTime | LTextLogLevel), WithLayout(time.RFC3339)}
+	writeRolloverFiles(t, name, base, func(writer WriteSyncer) LogHandler {
+		return NewTextHandlerWithOptions(writer, opts...)
+	})
+	rollover := NewLogFileRollover(name, 1, 0, 0, false)
+
+	// 配置不一致无法解析时间 设置时间区间时全部跳过 而不是全部保留
+	reader, err := rollover.OpenReader(base.Add(time.Minute), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readMessages(t, reader, NewTextDecoderWithOptions(opts...)); len(got) != 0 {
+		t.Errorf("messages = %q, want none", got)
+	}
+}
+
+func TestLogFileRolloverOpenReaderContinuation(t *testing.T) {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local)
+	opts := []Options{WithTextFlag(LTextTime | LTextLogLevel), WithLayout(time.RFC3339)}
+	newHandler := func(writer WriteSyncer) LogHandler {
+		return NewTextHandlerWithOptions(writer, opts...)
+	}
+	name := filepath.Join(t.TempDir(), "app.log")
+	// 调用栈 panic 输出以及换行的文本紧跟在日志行之后
+	var data []byte
+	data = append(data, "orphan before any entry\n"...)
+	data = append(data, writeLogLines(t, newHandler, base, "before")...)
+	data = append(data, "goroutine 1 [running]:\n\tmain.go:10\n"...)
+	data = append(data, writeLogLines(t, newHandler, base.Add(time.Minute), "inside")...)
+	data = append(data, "panic: boom\n\tmain.go:20\n\n"...)
+	data = append(data, writeLogLines(t, newHandler, base.Add(2*time.Minute), "after")...)
+	data = append(data, "\tmain.go:30\n"...)
+	if err := os.WriteFile(name, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rollover := NewLogFileRollover(name, 1, 0, 0, false)
+	reader, err := rollover.OpenReader(base.Add(30*time.Second), base.Add(90*time.Second), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inside := string(writeLogLines(t, newHandler, base.Add(time.Minute), "inside"))
+	if want := inside + "panic: boom\n\tmain.go:20\n\n"; string(got) != want {
+		t.Errorf("read %q, want %q", got, want)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 	"sync"
 	"time"
 
@@ -18,6 +19,11 @@ var (
 	_ LogHandler = (*commonHandler)(nil)
 )
 
+const (
+	// 超出单条日志最大长度时截断消息的最大重试次数
+	maxEntrySizeRetry = 3
+)
+
 // LogHandler 日志处理
 type LogHandler interface {
 	//Enabled 针对每个 LogHandler 处理不同的日志级别
@@ -35,6 +41,11 @@ type commonHandler struct {
 	mutex       sync.Mutex
 	writeSyncer WriteSyncer
 	options     *LogOptions
+	// 预先编码的静态字段 由子类提供编码方法
+	staticFields  []byte
+	encodeStatics func(fields []LogField, options *LogOptions) []byte
+	// 是否已关闭 关闭后 Sync/Close 不再调用 writeSyncer
+	closed bool
 }
 
 // newCommonHandler 实例化commonHandler方法 基类 不对外
@@ -64,6 +75,11 @@ func newCommonHandler(writeSyncer WriteSyncer, options *LogOptions) *commonHandl
 
 // Enabled 判断日志是否需要输出
 func (c *commonHandler) Enabled(ctx context.Context, level LogLevel) bool {
+	// context 携带日志级别覆盖 忽略配置的日志级别
+	if override, ok := LevelOverrideFromContext(ctx); ok {
+		return level >= override
+	}
+
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
@@ -81,8 +97,15 @@ func (c *commonHandler) LogRecord(ctx context.Context, entry *LogEntry) error {
 	return nil
 }
 
-// Close 关闭对应 Handler
+// Close 关闭对应 Handler 重复调用只关闭一次 writeSyncer
 func (c *commonHandler) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
 	return c.writeSyncer.Close()
 }
 
@@ -94,16 +117,67 @@ func (c *commonHandler) WithOptions(opts ...Options) {
 	for _, optFunc := range opts {
 		optFunc.apply(c.options)
 	}
+	c.refreshStaticFields()
+}
+
+// setStaticEncoder 设置静态字段编码方法 并编码当前配置的静态字段
+func (c *commonHandler) setStaticEncoder(encode func(fields []LogField, options *LogOptions) []byte) {
+	c.encodeStatics = encode
+	c.refreshStaticFields()
+}
+
+// refreshStaticFields 配置变更后重新编码静态字段
+func (c *commonHandler) refreshStaticFields() {
+	if c.encodeStatics == nil || len(c.options.StaticFields) == 0 {
+		c.staticFields = nil
+		return
+	}
+	c.staticFields = c.encodeStatics(c.options.StaticFields, c.options)
 }
 
-// Sync 强制同步
+// Sync 强制同步 关闭后忽略
 func (c *commonHandler) Sync() error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	if c.closed {
+		return nil
+	}
 	return c.writeSyncer.Sync()
 }
 
+// encodeEntry 编码一条日志 处理消息最大长度以及单条日志最大长度
+// 超出 MaxEntrySize 时丢弃全部字段重新编码 仍然超出则继续截断消息
+func (c *commonHandler) encodeEntry(buffer *pool.Buffer, entry *LogEntry,
+	appendEntry func(buffer *pool.Buffer, entry *LogEntry, msg string, dropFields, truncated bool)) {
+	msg, more := truncateString(entry.Msg, c.options.MaxMessageLength)
+	if more > 0 {
+		msg += fmt.Sprintf(truncatedBytesFormat, more)
+	}
+	appendEntry(buffer, entry, msg, false, more > 0)
+
+	maxSize := c.options.MaxEntrySize
+	if maxSize <= 0 || buffer.Len() <= maxSize {
+		return
+	}
+	// 丢弃全部字段
+	buffer.Reset()
+	appendEntry(buffer, entry, msg, true, true)
+	// 截断消息 转义可能导致长度估算不准 最多重试数次
+	keep := len(entry.Msg) - more
+	for retry := 0; retry < maxEntrySizeRetry && buffer.Len() > maxSize && keep > 0; retry++ {
+		// 扣除超出部分以及截断标记的长度
+		keep -= buffer.Len() - maxSize + len(fmt.Sprintf(truncatedBytesFormat, len(entry.Msg)))
+		var cut string
+		cut, more = cutString(entry.Msg, keep)
+		keep = len(cut)
+		msg = cut + fmt.Sprintf(truncatedBytesFormat, more)
+
+		buffer.Reset()
+		appendEntry(buffer, entry, msg, true, true)
+	}
+}
+
 // TextHandler 文本格式日志处理
 type TextHandler struct {
 	*commonHandler
@@ -114,6 +188,7 @@ func NewTextHandlerWithOptions(writeSyncer WriteSyncer, opts ...Options) *TextHa
 	instance := &TextHandler{
 		commonHandler: newCommonHandlerWithOptions(writeSyncer, opts...),
 	}
+	instance.setStaticEncoder(encodeTextStaticFields)
 
 	return instance
 }
@@ -123,14 +198,29 @@ func NewTextHandler(writeSyncer WriteSyncer, options *LogOptions) *TextHandler {
 	instance := &TextHandler{
 		commonHandler: newCommonHandler(writeSyncer, options),
 	}
+	instance.setStaticEncoder(encodeTextStaticFields)
 
 	return instance
 }
 
+// LogRecord 记录日志
 func (t *TextHandler) LogRecord(_ context.Context, entry *LogEntry) error {
 	buffer := bufferPool.Get()
 	defer buffer.Free()
 
+	// 编码读取 options 以及 staticFields WithOptions 会在锁内修改
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.encodeEntry(buffer, entry, t.appendEntry)
+
+	_, err := t.writeSyncer.Write(buffer.Bytes())
+	return err
+}
+
+// appendEntry 写入一条文本日志 dropFields 为 true 时丢弃全部字段
+func (t *TextHandler) appendEntry(buffer *pool.Buffer, entry *LogEntry, msg string, dropFields, truncated bool) {
+	encoder := newTextEncoder(buffer, t.options)
 	// 前缀
 	if t.options.TextPrefix != "" {
 		buffer.AppendByte(serializePrefixBegin)
@@ -141,11 +231,7 @@ func (t *TextHandler) LogRecord(_ context.Context, entry *LogEntry) error {
 	}
 	// 时间
 	if !entry.Time.IsZero() && t.options.TextFlag&LTextTime != 0 {
-		layout := t.options.Layout
-		if layout == "" {
-			layout = DefaultTimeLayout
-		}
-		buffer.AppendString(entry.Time.Format(layout))
+		encoder.appendTime(entry.Time)
 		buffer.AppendByte(serializeSpaceSplit)
 		// <prefix> 2006/01/02 15:04:05.000000<space>
 	}
@@ -153,7 +239,9 @@ func (t *TextHandler) LogRecord(_ context.Context, entry *LogEntry) error {
 	if t.options.TextFlag&lCheckLogLevel != 0 {
 		logLevel := entry.Level
 		buffer.AppendByte(serializeArrayBegin)
-		if t.options.TextFlag&LTextLogLevel != 0 {
+		if t.options.LevelEncoder != nil {
+			t.options.LevelEncoder(logLevel, (*textPrimitiveEncoder)(encoder))
+		} else if t.options.TextFlag&LTextLogLevel != 0 {
 			buffer.AppendString(logLevel.CapitalString())
 		} else if t.options.TextFlag&LTextLogLevelUpCase != 0 {
 			buffer.AppendString(logLevel.UpCaseString())
@@ -185,30 +273,50 @@ func (t *TextHandler) LogRecord(_ context.Context, entry *LogEntry) error {
 	}
 	// Message
 	{
-		buffer.AppendString(entry.Msg)
+		// 可能与字段混淆的消息加引号
+		if textMessageNeedsQuote(msg) {
+			buffer.AppendString(strconv.Quote(msg))
+		} else {
+			buffer.AppendString(msg)
+		}
 		buffer.AppendByte(serializeSpaceSplit)
 		// <prefix> 2006/01/02 15:04:05.000000 [Level] file:line message<space>
 	}
-	// Fields
-	for _, field := range entry.Fields {
-		data, err := field.MarshalText()
-		if err != nil {
-			buffer.AppendString(fmt.Sprintf("%s=Error:%s", field.Key, err.Error()))
-			continue
+	// 静态字段以及 Fields 配置了去重或排列时两者合并后统一处理
+	var arranged []arrangedField
+	if !dropFields {
+		arranged = arrangeEntryFields(t.options.StaticFields, entry.Fields, t.options)
+	}
+	switch {
+	case dropFields:
+		// 静态字段
+		buffer.AppendBytes(t.staticFields)
+	case arranged != nil:
+		for _, field := range arranged {
+			encoder.appendField(field.LogField)
+			buffer.AppendByte(serializeSpaceSplit)
 		}
-		buffer.AppendBytes(data)
+		truncated = truncated || encoder.truncated
+	default:
+		// 静态字段
+		buffer.AppendBytes(t.staticFields)
+		// Fields
+		for _, field := range arrangeFields(entry.Fields, t.options) {
+			encoder.appendField(field)
+			buffer.AppendByte(serializeSpaceSplit)
+			//  <prefix> 2024/06/11 10:00:00.000000 [Info] file:line function<space>message fieldKey=fieldValue...<space>
+		}
+		truncated = truncated || encoder.truncated
+	}
+	// 截断标记
+	if truncated || dropFields {
+		buffer.AppendString(truncatedFieldKey)
+		buffer.AppendByte(serializeFieldStep)
+		buffer.AppendBool(true)
 		buffer.AppendByte(serializeSpaceSplit)
-		//  <prefix> 2024/06/11 10:00:00.000000 [Info] file:line function<space>message fieldKey=fieldValue...<space>
 	}
 	// new line
 	buffer.AppendByte(serializeNewLine)
-
-	fmt.Println(buffer.String())
-
-	t.mutex.Lock()
-	defer t.mutex.Unlock()
-	_, err := t.writeSyncer.Write(buffer.Bytes())
-	return err
 }
 
 // JsonHandler JSON格式日志处理
@@ -221,6 +329,7 @@ func NewJsonHandlerWithOptions(writeSyncer WriteSyncer, opts ...Options) *JsonHa
 	instance := &JsonHandler{
 		newCommonHandlerWithOptions(writeSyncer, opts...),
 	}
+	instance.setStaticEncoder(encodeJsonStaticFields)
 	return instance
 }
 
@@ -229,6 +338,7 @@ func NewJsonHandler(writeSyncer WriteSyncer, options *LogOptions) *JsonHandler {
 	instance := &JsonHandler{
 		newCommonHandler(writeSyncer, options),
 	}
+	instance.setStaticEncoder(encodeJsonStaticFields)
 	return instance
 }
 
@@ -240,48 +350,78 @@ func (j *JsonHandler) LogRecord(_ context.Context, entry *LogEntry) error {
 	j.mutex.Lock()
 	defer j.mutex.Unlock()
 
+	j.encodeEntry(buffer, entry, j.appendEntry)
+
+	_, err := j.writeSyncer.Write(buffer.Bytes())
+
+	return err
+}
+
+// appendEntry 写入一条Json日志 dropFields 为 true 时丢弃全部字段
+func (j *JsonHandler) appendEntry(buffer *pool.Buffer, entry *LogEntry, msg string, dropFields, truncated bool) {
+	file, line, function := entry.Source()
+	j.appendSourceEntry(buffer, entry, fmt.Sprintf("%s:%d %s", file, line, function), msg, dropFields, truncated)
+}
+
+// appendSourceEntry 写入一条Json日志 使用给定的源码位置 source 为空时不输出
+func (j *JsonHandler) appendSourceEntry(buffer *pool.Buffer, entry *LogEntry, source, msg string, dropFields, truncated bool) {
+	encoder := newJsonEncoder(buffer, j.options)
 	buffer.AppendByte(serializeJsonStart)
 	// 时间
 	if !entry.Time.IsZero() {
-		layout := j.options.Layout
-		if layout == "" {
-			layout = DefaultTimeLayout
-		}
 		key := j.options.TimeEncodeKey
 		if key == "" {
 			key = defaultJsonTimeKey
 		}
 		j.appendJsonKey(buffer, key)
-		j.appendJsonValue(buffer, entry.Time.Format(layout))
+		encoder.appendTime(entry.Time)
 	}
 	// source
-	{
+	if source != "" {
 		key := j.options.SourceEncodeKey
 		if key == "" {
 			key = defaultJsonSourceKey
 		}
 		j.appendJsonKey(buffer, key)
-		file, line, function := entry.Source()
-		sourceStr := fmt.Sprintf("%s:%d %s", file, line, function)
-		j.appendJsonValue(buffer, sourceStr)
+		j.appendJsonValue(buffer, source)
 	}
 	// 日志级别
 	{
-		key := j.options.SourceEncodeKey
+		key := j.options.LevelEncodeKey
 		if key == "" {
 			key = defaultJsonLevelKey
 		}
 		j.appendJsonKey(buffer, key)
-		j.appendJsonValue(buffer, entry.Level.LowCaseString())
+		levelEncoder := j.options.LevelEncoder
+		if levelEncoder == nil {
+			levelEncoder = LowercaseLevelEncoder
+		}
+		levelEncoder(entry.Level, (*jsonPrimitiveEncoder)(encoder))
 	}
 	// Message
 	{
-		key := j.options.SourceEncodeKey
+		key := j.options.MessageEncodeKey
 		if key == "" {
 			key = defaultJsonMessageKey
 		}
 		j.appendJsonKey(buffer, key)
-		j.appendJsonValue(buffer, entry.Msg)
+		appendJsonString(buffer, msg)
+	}
+	// 静态字段以及 fields 配置了去重或排列时两者合并后统一处理 静态字段仍位于顶层
+	var arranged []arrangedField
+	if !dropFields {
+		arranged = arrangeEntryFields(j.options.StaticFields, entry.Fields, j.options)
+	}
+	// 静态字段 ,"k":v,"k2":v2
+	if arranged == nil {
+		buffer.AppendBytes(j.staticFields)
+	} else {
+		encoder.empty = false
+		for _, field := range arranged {
+			if field.static {
+				encoder.appendStaticField(field.LogField)
+			}
+		}
 	}
 	// fields...
 	{
@@ -290,14 +430,31 @@ func (j *JsonHandler) LogRecord(_ context.Context, entry *LogEntry) error {
 			key = defaultJsonFieldsKey
 		}
 		j.appendJsonKey(buffer, key)
-		j.appendJsonValue(buffer, entry.Fields)
+		state := encoder.appendArrayBegin()
+		switch {
+		case dropFields:
+		case arranged != nil:
+			for _, field := range arranged {
+				if !field.static {
+					encoder.appendSeparator()
+					encoder.appendField(field.LogField)
+				}
+			}
+		default:
+			for _, field := range arrangeFields(entry.Fields, j.options) {
+				encoder.appendSeparator()
+				encoder.appendField(field)
+			}
+		}
+		// 截断标记 [..., {"_truncated":true}]
+		if truncated || dropFields || encoder.truncated {
+			encoder.appendSeparator()
+			encoder.appendField(Bool(truncatedFieldKey, true))
+		}
+		encoder.appendArrayEnd(state)
 	}
 	buffer.AppendByte(serializeJsonEnd)
 	buffer.AppendByte(serializeNewLine)
-
-	_, err := j.writeSyncer.Write(buffer.Bytes())
-
-	return err
 }
 
 // appendJsonKey 往buffer写入 "key":
@@ -331,28 +488,7 @@ func (j *JsonHandler) appendJsonValue(buffer *pool.Buffer, val any) {
 	// 如果有定制
 	switch vv := val.(type) {
 	case time.Time:
-		buffer.AppendByte(serializeStringMarks)
-		layout := j.options.TimeEncodeKey
-		if layout == "" {
-			layout = DefaultTimeLayout
-		}
-		buffer.AppendString(vv.Format(layout))
-		buffer.AppendByte(serializeStringMarks)
-	case []LogField:
-		// 其实这个可以不需要 LogField 已经实现了 json.Marshaler 接口
-		// 在调用 jsonEncoder.Encode 的时候判断是否实现 json.Marshaler 然后会自动调用 MarshalJSON
-		buffer.AppendByte(serializeArrayBegin)
-		for idx, field := range vv {
-			if idx > 0 {
-				buffer.AppendByte(serializeCommaStep)
-			}
-			data, err := field.MarshalJSON()
-			if err != nil {
-				panic(err)
-			}
-			buffer.AppendBytes(data)
-		}
-		buffer.AppendByte(serializeArrayEnd)
+		newJsonEncoder(buffer, j.options).appendTime(vv)
 	default:
 		// 默认
 		data, err := j.appendJsonMarshal(val)
@@ -0,0 +1,155 @@
+package gslog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHandlerWithOptionsConcurrent(t *testing.T) {
+	handlers := map[string]interface {
+		LogHandler
+		WithOptions(opts ...Options)
+	}{
+		"text": NewTextHandlerWithOptions(&bufferWriteSyncer{}, WithLevel(InfoLevel)),
+		"json": NewJsonHandlerWithOptions(&bufferWriteSyncer{}, WithLevel(InfoLevel)),
+	}
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			logger := NewLogger(handler)
+
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				<-start
+				for idx := 0; idx < 1000; idx++ {
+					handler.WithOptions(WithStaticFields(String("round", strconv.Itoa(idx))), WithPrefix("p"+strconv.Itoa(idx)))
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				<-start
+				for idx := 0; idx < 1000; idx++ {
+					logger.LogFields(context.Background(), InfoLevel, "msg", Int("idx", idx))
+				}
+			}()
+			close(start)
+			wg.Wait()
+		})
+	}
+}
+
+// limitPoint Any 字段截断测试类型
+type limitPoint struct {
+	X, Y int
+}
+
+func TestHandlerSizeLimits(t *testing.T) {
+	wrapped := fmt.Errorf("wrapped: %w", errors.New("base error"))
+	tests := []struct {
+		name   string
+		option Options
+		msg    string
+		field  LogField
+		text   string
+		json   string
+	}{
+		{"message", WithMaxMessageLength(5), "hello world", String("s", "abc"),
+			"hello...(6 more bytes) s=abc", `"message":"hello...(6 more bytes)"`},
+		{"string", WithMaxStringLength(5), "msg", String("s", "abcdefgh"),
+			"s=abcde...(3 more bytes)", `{"s":"abcde...(3 more bytes)"}`},
+		{"any", WithMaxStringLength(5), "msg", Any("p", limitPoint{1234, 5678}),
+			`p="{1234...(6 more bytes)"`, `{"p":"{\"X\":...(14 more bytes)"}`},
+		{"error", WithMaxStringLength(5), "msg", Err(wrapped),
+			"error=err: wrapp...(14 more bytes) error.causes=[base ...(5 more bytes)]",
+			`{"error":{"message":"wrapp...(14 more bytes)","causes":[{"message":"base ...(5 more bytes)"}]}}`},
+		{"binary", WithMaxBinaryLength(2), "msg", Binary("b", []byte{1, 2, 3, 4}),
+			"b=0102...(2 more bytes)", `{"b":"AQI=...(2 more bytes)"}`},
+		{"array", WithMaxArrayElements(2), "msg", Slice("n", []int{1, 2, 3, 4}),
+			"n=[1, 2, ...(2 more elements)]", `{"n":[1,2,"...(2 more elements)"]}`},
+		{"depth nested field", WithMaxDepth(1), "msg", Fields("g", Fields("h", Int("i", 1))),
+			"g.h=...(max depth exceeded)", `{"g":{"h":"...(max depth exceeded)"}}`},
+		{"depth field group", WithMaxDepth(1), "msg", Fields("g", Int("a", 1), Fields("h", Int("i", 1), Int("j", 2))),
+			"g=[a=1, h=...(max depth exceeded)]", `{"g":[{"a":1},{"h":"...(max depth exceeded)"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			textWriter, jsonWriter := &bufferWriteSyncer{}, &bufferWriteSyncer{}
+			NewLogger(NewTextHandlerWithOptions(textWriter, WithTextFlag(LTextLogLevel), tt.option)).Info(tt.msg, tt.field)
+			NewLogger(NewJsonHandlerWithOptions(jsonWriter, tt.option)).Info(tt.msg, tt.field)
+
+			if text := textWriter.String(); !strings.Contains(text, tt.text) || !strings.Contains(text, "_truncated=true") {
+				t.Errorf("text output %q, want %q and _truncated=true", text, tt.text)
+			}
+			if data := jsonWriter.String(); !strings.Contains(data, tt.json) || !strings.Contains(data, `{"_truncated":true}`) {
+				t.Errorf("json output %s, want %s and _truncated", data, tt.json)
+			}
+		})
+	}
+
+	// 未超出限制时不截断
+	textWriter, jsonWriter := &bufferWriteSyncer{}, &bufferWriteSyncer{}
+	limits := []Options{WithMaxMessageLength(3), WithMaxStringLength(3), WithMaxArrayElements(3), WithMaxDepth(2)}
+	fields := []any{String("s", "abc"), Any("p", []int{1}), Slice("n", []int{1, 2, 3}), Fields("g", Fields("h", Int("i", 1)))}
+	NewLogger(NewTextHandlerWithOptions(textWriter, append(limits, WithTextFlag(LTextLogLevel))...)).Info("msg", fields...)
+	NewLogger(NewJsonHandlerWithOptions(jsonWriter, limits...)).Info("msg", fields...)
+	for name, output := range map[string]string{"text": textWriter.String(), "json": jsonWriter.String()} {
+		if strings.Contains(output, "_truncated") || strings.Contains(output, "...(") {
+			t.Errorf("%s output truncated within limits: %s", name, output)
+		}
+	}
+}
+
+func TestHandlerMaxEntrySize(t *testing.T) {
+	handlers := map[string]func(writer WriteSyncer, opts ...Options) LogHandler{
+		"text": func(writer WriteSyncer, opts ...Options) LogHandler {
+			return NewTextHandlerWithOptions(writer, append(opts, WithTextFlag(DefaultLTextFlag))...)
+		},
+		"json": func(writer WriteSyncer, opts ...Options) LogHandler {
+			return NewJsonHandlerWithOptions(writer, opts...)
+		},
+	}
+	for name, newHandler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			// 时间与调用位置的长度随环境变化 以未限制时的长度为基准
+			baseWriter := &bufferWriteSyncer{}
+			NewLogger(newHandler(baseWriter)).Info("short", Int("n", 1))
+			maxSize := len(baseWriter.String()) + 32
+
+			writer := &bufferWriteSyncer{}
+			logger := NewLogger(newHandler(writer, WithMaxEntrySize(maxSize)))
+			// 未超出时原样输出
+			logger.Info("short", Int("n", 1))
+			// 字段过长时丢弃全部字段 保留消息
+			logger.Info("fields", String("long", strings.Repeat("x", maxSize)), Int("n", 1))
+			// 消息过长时继续截断消息 转义后的长度同样受限
+			logger.Info(strings.Repeat("a\"\n", maxSize), Int("n", 1))
+
+			lines := writer.Lines()
+			if len(lines) != 3 {
+				t.Fatalf("got %d lines: %q", len(lines), writer.String())
+			}
+			for idx, line := range lines {
+				if len(line)+1 > maxSize {
+					t.Errorf("line %d size %d exceeds %d: %s", idx, len(line)+1, maxSize, line)
+				}
+			}
+			if strings.Contains(lines[0], "_truncated") {
+				t.Errorf("short entry truncated: %s", lines[0])
+			}
+			if !strings.Contains(lines[1], "fields") || strings.Contains(lines[1], "xxx") || !strings.Contains(lines[1], "_truncated") {
+				t.Errorf("fields not dropped: %s", lines[1])
+			}
+			if !strings.Contains(lines[2], "more bytes)") || !strings.Contains(lines[2], "_truncated") ||
+				strings.Contains(lines[2], "n=1") || strings.Contains(lines[2], `"n":1`) {
+				t.Errorf("message not truncated: %s", lines[2])
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package gslog
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// 检查 httpResponseWriter 实现 http.ResponseWriter
+	_ http.ResponseWriter = (*httpResponseWriter)(nil)
+	// 检查 httpResponseWriter 实现 http.Flusher
+	_ http.Flusher = (*httpResponseWriter)(nil)
+	// 检查 httpResponseWriter 实现 http.Hijacker
+	_ http.Hijacker = (*httpResponseWriter)(nil)
+)
+
+const (
+	// 默认请求ID header
+	defaultRequestIDHeader = "X-Request-ID"
+	// 请求ID字段key
+	requestIDFieldKey = "request_id"
+	// Apache/NGINX 访问日志时间格式
+	accessLogTimeLayout = "02/Jan/2006:15:04:05 -0700"
+	// 上游传递的请求ID最大长度 超出或包含不可见字符时重新生成
+	maxRequestIDLength = 128
+	// 查找 panic 位置时读取的最大调用栈深度
+	maxPanicStackDepth = 32
+)
+
+// HTTPAccessFormat HTTP访问日志输出格式
+type HTTPAccessFormat int
+
+const (
+	HTTPAccessStructured HTTPAccessFormat = iota // 结构化字段输出
+	HTTPAccessCombined                           // Apache combined 格式
+	HTTPAccessNginx                              // NGINX 默认 main 格式
+)
+
+// HTTPOptions HTTP中间件配置
+type HTTPOptions struct {
+	// 请求ID header 默认 X-Request-ID
+	RequestIDHeader string
+	// 请求ID生成方法 默认随机16字节hex
+	GenerateRequestID func() string
+	// 访问日志格式
+	AccessFormat HTTPAccessFormat
+}
+
+// requestIDKey 请求ID context key
+type requestIDKey struct{}
+
+// RequestIDFromContext 获取 context 携带的请求ID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok
+}
+
+// HTTPMiddleware 创建 net/http 请求日志中间件
+// 记录请求方法/路径/状态码/写入字节/耗时/来源地址/UserAgent 并向请求 context 注入携带请求ID的日志器
+// 5xx 输出 ErrorLevel 4xx 输出 WarnLevel 其余输出 InfoLevel
+// 处理过程中发生 panic 会被恢复并输出携带堆栈的 ErrorLevel 日志
+// 访问日志的源码位置为被包装的 handler panic 日志的源码位置为发生 panic 的位置
+// 上游请求ID超过 128 字节或包含空白/控制字符时忽略并重新生成
+func HTTPMiddleware(logger *Logger, options *HTTPOptions) func(http.Handler) http.Handler {
+	if options == nil {
+		options = &HTTPOptions{}
+	}
+	header := options.RequestIDHeader
+	if header == "" {
+		header = defaultRequestIDHeader
+	}
+	generate := options.GenerateRequestID
+	if generate == nil {
+		generate = generateRequestID
+	}
+
+	return func(next http.Handler) http.Handler {
+		pc := handlerPC(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// 请求ID 优先沿用上游传递
+			requestID := r.Header.Get(header)
+			if !validRequestID(requestID) {
+				requestID = generate()
+			}
+			w.Header().Set(header, requestID)
+
+			reqLogger := logger.With(String(requestIDFieldKey, requestID))
+			ctx := context.WithValue(r.Context(), requestIDKey{}, requestID)
+			ctx = NewContext(ctx, reqLogger)
+			r = r.WithContext(ctx)
+
+			rw := &httpResponseWriter{ResponseWriter: w}
+			defer func() {
+				if rec := recover(); rec != nil {
+					// http.ErrAbortHandler 用于主动中断 保持原有行为
+					if rec == http.ErrAbortHandler {
+						panic(rec)
+					}
+					if !rw.wroteHeader {
+						rw.WriteHeader(http.StatusInternalServerError)
+					}
+					logHTTPPanic(reqLogger, ctx, panicPC(pc), r, rec)
+				}
+				logHTTPAccess(reqLogger, ctx, pc, options.AccessFormat, r, rw, start)
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// logHTTPPanic 输出 panic 日志 携带堆栈
+func logHTTPPanic(logger *Logger, ctx context.Context, pc uintptr, r *http.Request, rec any) {
+	if !logger.Enabled(ctx, ErrorLevel) {
+		return
+	}
+	logger.logFieldsPC(ctx, ErrorLevel, pc, "http handler panic",
+		String("panic", fmt.Sprint(rec)),
+		String("stack", debug.Stack()),
+		String("method", r.Method),
+		String("path", r.URL.Path),
+	)
+}
+
+// logHTTPAccess 输出访问日志
+func logHTTPAccess(logger *Logger, ctx context.Context, pc uintptr, format HTTPAccessFormat, r *http.Request, rw *httpResponseWriter, start time.Time) {
+	status := rw.Status()
+	level := InfoLevel
+	switch {
+	case status >= http.StatusInternalServerError:
+		level = ErrorLevel
+	case status >= http.StatusBadRequest:
+		level = WarnLevel
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	switch format {
+	case HTTPAccessCombined, HTTPAccessNginx:
+		logger.logFieldsPC(ctx, level, pc, formatAccessLine(format, r, rw, start))
+	default:
+		logger.logFieldsPC(ctx, level, pc, "http request",
+			String("method", r.Method),
+			String("path", r.URL.Path),
+			Int("status", status),
+			Int("bytes", rw.written),
+			Duration("duration", time.Since(start)),
+			String("remote_addr", r.RemoteAddr),
+			String("user_agent", r.UserAgent()),
+		)
+	}
+}
+
+// formatAccessLine 格式化 Apache combined/NGINX main 访问日志
+func formatAccessLine(format HTTPAccessFormat, r *http.Request, rw *httpResponseWriter, start time.Time) string {
+	var builder strings.Builder
+
+	host := r.RemoteAddr
+	if idx := strings.LastIndexByte(host, serializeColonSplit); idx > 0 && !strings.HasSuffix(host, "]") {
+		host = host[:idx]
+	}
+	user := "-"
+	if r.URL.User != nil && r.URL.User.Username() != "" {
+		user = r.URL.User.Username()
+	} else if name, _, ok := r.BasicAuth(); ok && name != "" {
+		user = name
+	}
+	bytes := "-"
+	if rw.written > 0 || format == HTTPAccessNginx {
+		bytes = strconv.Itoa(rw.written)
+	}
+
+	// host - user [time] "method uri proto" status bytes "referer" "user-agent"
+	builder.WriteString(host)
+	builder.WriteString(" - ")
+	builder.WriteString(user)
+	builder.WriteString(" [")
+	builder.WriteString(start.Format(accessLogTimeLayout))
+	builder.WriteString("] ")
+	builder.WriteString(strconv.Quote(fmt.Sprintf("%s %s %s", r.Method, r.RequestURI, r.Proto)))
+	builder.WriteByte(serializeSpaceSplit)
+	builder.WriteString(strconv.Itoa(rw.Status()))
+	builder.WriteByte(serializeSpaceSplit)
+	builder.WriteString(bytes)
+	builder.WriteByte(serializeSpaceSplit)
+	builder.WriteString(strconv.Quote(headerOrDash(r.Referer())))
+	builder.WriteByte(serializeSpaceSplit)
+	builder.WriteString(strconv.Quote(headerOrDash(r.UserAgent())))
+	if format == HTTPAccessNginx {
+		// "x-forwarded-for"
+		builder.WriteByte(serializeSpaceSplit)
+		builder.WriteString(strconv.Quote(headerOrDash(r.Header.Get("X-Forwarded-For"))))
+	}
+
+	return builder.String()
+}
+
+// headerOrDash 空值使用 - 代替
+func headerOrDash(val string) string {
+	if val == "" {
+		return "-"
+	}
+	return val
+}
+
+// validRequestID 上游请求ID是否可用 非空 不超过最大长度 只包含可见ASCII字符
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for idx := 0; idx < len(requestID); idx++ {
+		if requestID[idx] <= ' ' || requestID[idx] >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// handlerPC 被包装 handler 的函数入口 用作访问日志的源码位置
+// http.HandlerFunc 使用函数本身 其余类型使用 ServeHTTP 方法
+func handlerPC(next http.Handler) uintptr {
+	var entry uintptr
+	if fn, ok := next.(http.HandlerFunc); ok {
+		entry = reflect.ValueOf(fn).Pointer()
+	} else if method, ok := reflect.TypeOf(next).MethodByName("ServeHTTP"); ok {
+		entry = method.Func.Pointer()
+	}
+	if entry == 0 {
+		return 0
+	}
+	// CallersFrames 按返回地址处理 入口地址需要加一才能定位到函数本身
+	return entry + 1
+}
+
+// panicPC 在 recover 所在的 defer 中查找发生 panic 的位置 找不到时返回 fallback
+func panicPC(fallback uintptr) uintptr {
+	var pcs [maxPanicStackDepth]uintptr
+	// runtime.Callers. this function, this function's Caller
+	count := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:count])
+	panicking := false
+	for {
+		frame, more := frames.Next()
+		if panicking && !strings.HasPrefix(frame.Function, "runtime.") {
+			return frame.PC + 1
+		}
+		if frame.Function == "runtime.gopanic" {
+			panicking = true
+		}
+		if !more {
+			return fallback
+		}
+	}
+}
+
+// generateRequestID 生成随机请求ID
+func generateRequestID() string {
+	var data [16]byte
+	if _, err := rand.Read(data[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(data[:])
+}
+
+// httpResponseWriter 记录状态码与写入字节数
+type httpResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	written     int
+	wroteHeader bool
+}
+
+// WriteHeader 实现 http.ResponseWriter
+func (h *httpResponseWriter) WriteHeader(statusCode int) {
+	if !h.wroteHeader {
+		h.status = statusCode
+		h.wroteHeader = true
+	}
+	h.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write 实现 http.ResponseWriter
+func (h *httpResponseWriter) Write(data []byte) (int, error) {
+	if !h.wroteHeader {
+		h.WriteHeader(http.StatusOK)
+	}
+	n, err := h.ResponseWriter.Write(data)
+	h.written += n
+	return n, err
+}
+
+// Status 响应状态码 未写入时默认 200
+func (h *httpResponseWriter) Status() int {
+	if h.status == 0 {
+		return http.StatusOK
+	}
+	return h.status
+}
+
+// Unwrap 支持 http.ResponseController 访问原始 ResponseWriter
+func (h *httpResponseWriter) Unwrap() http.ResponseWriter {
+	return h.ResponseWriter
+}
+
+// Flush 实现 http.Flusher 例如 SSE 原始 ResponseWriter 不支持时忽略
+func (h *httpResponseWriter) Flush() {
+	if !h.wroteHeader {
+		h.WriteHeader(http.StatusOK)
+	}
+	_ = http.NewResponseController(h.ResponseWriter).Flush()
+}
+
+// Hijack 实现 http.Hijacker 例如 websocket 原始 ResponseWriter 不支持时返回 http.ErrNotSupported
+func (h *httpResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(h.ResponseWriter).Hijack()
+}
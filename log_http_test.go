@@ -0,0 +1,190 @@
+package gslog
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// decodeJsonLines 解析 JsonHandler 输出
+func decodeJsonLines(t *testing.T, writer *bufferWriteSyncer) []map[string]any {
+	t.Helper()
+
+	var entries []map[string]any
+	for _, line := range writer.Lines() {
+		entry := make(map[string]any)
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid json line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// currentLine 调用处行号
+func currentLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func TestHTTPMiddlewareSource(t *testing.T) {
+	writer := &bufferWriteSyncer{}
+	logger := NewLogger(NewJsonHandlerWithOptions(writer, WithLevel(InfoLevel)))
+
+	var panicLine int
+	handler := HTTPMiddleware(logger, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/panic" {
+			panicLine = currentLine() + 1
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, path := range []string{"/ok", "/panic"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	entries := decodeJsonLines(t, writer)
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3: %v", len(entries), writer.Lines())
+	}
+	for _, entry := range entries {
+		source, _ := entry["source"].(string)
+		if !strings.Contains(source, "log_http_test.go:") {
+			t.Errorf("entry %q source = %q, want location in log_http_test.go", entry["message"], source)
+		}
+	}
+	// panic 日志定位到 panic 语句
+	if source := entries[1]["source"].(string); !strings.Contains(source, "log_http_test.go:"+strconv.Itoa(panicLine)+" ") {
+		t.Errorf("panic source = %q, want line %d", source, panicLine)
+	}
+}
+
+func TestHTTPMiddlewareRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		inbound  string
+		generate bool
+	}{
+		{name: "propagate", inbound: "req-123_ABC.4"},
+		{name: "empty", inbound: "", generate: true},
+		{name: "too long", inbound: strings.Repeat("a", maxRequestIDLength+1), generate: true},
+		{name: "max length", inbound: strings.Repeat("a", maxRequestIDLength)},
+		{name: "control character", inbound: "abc\x1b[31m", generate: true},
+		{name: "space", inbound: "abc def", generate: true},
+		{name: "non ascii", inbound: "请求", generate: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bufferWriteSyncer{}
+			logger := NewLogger(NewJsonHandlerWithOptions(writer))
+			options := &HTTPOptions{GenerateRequestID: func() string { return "generated" }}
+
+			var fromContext string
+			handler := HTTPMiddleware(logger, options)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fromContext, _ = RequestIDFromContext(r.Context())
+			}))
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set(defaultRequestIDHeader, tt.inbound)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			want := tt.inbound
+			if tt.generate {
+				want = "generated"
+			}
+			if fromContext != want {
+				t.Errorf("context request id = %q, want %q", fromContext, want)
+			}
+			if got := recorder.Header().Get(defaultRequestIDHeader); got != want {
+				t.Errorf("response header = %q, want %q", got, want)
+			}
+			if !strings.Contains(writer.String(), `{"request_id":"`+want+`"}`) {
+				t.Errorf("log missing request id %q: %s", want, writer.String())
+			}
+		})
+	}
+}
+
+// plainResponseWriter 只实现 http.ResponseWriter 的原始 ResponseWriter
+type plainResponseWriter struct {
+	header http.Header
+}
+
+func (p *plainResponseWriter) Header() http.Header {
+	return p.header
+}
+
+func (p *plainResponseWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func (p *plainResponseWriter) WriteHeader(int) {}
+
+func TestHTTPMiddlewareFlush(t *testing.T) {
+	writer := &bufferWriteSyncer{}
+	logger := NewLogger(NewJsonHandlerWithOptions(writer, WithLevel(InfoLevel)))
+	handler := HTTPMiddleware(logger, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Errorf("ResponseWriter %T does not implement http.Flusher", w)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: 1\n\n"))
+		flusher.Flush()
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/events", nil))
+	if !recorder.Flushed || recorder.Body.String() != "data: 1\n\n" {
+		t.Errorf("flushed = %v body = %q", recorder.Flushed, recorder.Body.String())
+	}
+
+	// 原始 ResponseWriter 不支持时 Flush 忽略 Hijack 返回 http.ErrNotSupported
+	wrapped := &httpResponseWriter{ResponseWriter: &plainResponseWriter{header: http.Header{}}}
+	wrapped.Flush()
+	if wrapped.Status() != http.StatusOK {
+		t.Errorf("status after flush = %d, want 200", wrapped.Status())
+	}
+	if _, _, err := wrapped.Hijack(); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("Hijack error = %v, want %v", err, http.ErrNotSupported)
+	}
+}
+
+func TestHTTPMiddlewareHijack(t *testing.T) {
+	writer := &bufferWriteSyncer{}
+	logger := NewLogger(NewJsonHandlerWithOptions(writer, WithLevel(InfoLevel)))
+	server := httptest.NewServer(HTTPMiddleware(logger, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Errorf("ResponseWriter %T does not implement http.Hijacker", w)
+			return
+		}
+		conn, buf, err := hijacker.Hijack()
+		if err != nil {
+			t.Errorf("Hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+		_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+		_ = buf.Flush()
+	})))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "hijacked" {
+		t.Errorf("body = %q, %v, want hijacked", body, err)
+	}
+}
@@ -0,0 +1,625 @@
+package gslog
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"time"
+	"unicode/utf8"
+
+	"gslog/internal/bufferPool"
+	"gslog/pool"
+)
+
+const (
+	// json 转义使用的十六进制字符
+	jsonHex = "0123456789abcdef"
+)
+
+// jsonEncoder Json格式编码器 JsonHandler 以及 LogField.MarshalJSON 共用
+type jsonEncoder struct {
+	buffer  *pool.Buffer
+	options *LogOptions
+	// 当前容器是否尚未写入元素
+	empty bool
+	// 当前嵌套深度
+	depth int
+	// 当前数组写入状态
+	array arrayState
+	// 是否发生截断
+	truncated bool
+}
+
+// newJsonEncoder 实例化Json编码器 options 允许为 nil
+func newJsonEncoder(buffer *pool.Buffer, options *LogOptions) *jsonEncoder {
+	if options == nil {
+		options = &LogOptions{}
+	}
+	return &jsonEncoder{
+		buffer:  buffer,
+		options: options,
+		empty:   true,
+	}
+}
+
+// appendField 写入 {"key":value}
+func (j *jsonEncoder) appendField(field LogField) {
+	j.buffer.AppendByte(serializeJsonStart)
+	j.empty = true
+	j.appendKey(field.Key)
+	if err := j.appendValue(field.Value); err != nil {
+		// {"key":value,"keyError":"err"}
+		j.appendKey(field.Key + "Error")
+		j.appendString(err.Error())
+	}
+	j.buffer.AppendByte(serializeJsonEnd)
+	j.empty = false
+}
+
+// appendFieldList 写入 [{"k":v},{"k2":v2}]
+func (j *jsonEncoder) appendFieldList(fields []LogField) {
+	fields = arrangeFields(fields, j.options)
+	state := j.appendArrayBegin()
+	for _, field := range fields {
+		j.appendSeparator()
+		j.appendField(field)
+	}
+	j.appendArrayEnd(state)
+}
+
+// appendValue 写入字段值
+func (j *jsonEncoder) appendValue(val LogFieldValue) error {
+	// 确认输出后才解析 LogValuer
+	val = val.Resolve()
+	if nestedKind(val.Kind()) {
+		if !j.enterContainer() {
+			return nil
+		}
+		defer j.leaveContainer()
+	}
+	switch val.Kind() {
+	case LogFieldValueInt64:
+		j.buffer.AppendInt(val.Int64())
+	case LogFieldValueUint64:
+		j.buffer.AppendUint(val.Uint64())
+	case LogFieldValueFloat64:
+		j.appendFloat(val.Float64())
+	case LogFieldValueBool:
+		j.buffer.AppendBool(val.Bool())
+	case LogFieldValueString:
+		j.appendStringValue(val.String())
+	case LogFieldValueTime:
+		j.appendTime(val.Time())
+	case LogFieldValueDuration:
+		j.appendDuration(val.Duration())
+	case LogFieldValueError:
+		j.appendError(val.Error(), 0)
+	case LogFieldValueInt64s:
+		state := j.appendArrayBegin()
+		for _, num := range val.Int64s() {
+			j.AppendInt64(num)
+		}
+		j.appendArrayEnd(state)
+	case LogFieldValueUint64s:
+		state := j.appendArrayBegin()
+		for _, num := range val.Uint64s() {
+			j.AppendUint64(num)
+		}
+		j.appendArrayEnd(state)
+	case LogFieldValueFloat64s:
+		state := j.appendArrayBegin()
+		for _, num := range val.Float64s() {
+			j.AppendFloat64(num)
+		}
+		j.appendArrayEnd(state)
+	case LogFieldValueStrings:
+		state := j.appendArrayBegin()
+		for _, str := range val.Strings() {
+			j.AppendString(str)
+		}
+		j.appendArrayEnd(state)
+	case LogFieldValueBools:
+		state := j.appendArrayBegin()
+		for _, boolVal := range val.Bools() {
+			j.AppendBool(boolVal)
+		}
+		j.appendArrayEnd(state)
+	case LogFieldValueField:
+		j.appendField(val.Field())
+	case LogFieldValueFields:
+		j.appendFieldList(val.Fields())
+	case LogFieldValueObject:
+		return j.appendObject(val.Object())
+	case LogFieldValueArray:
+		return j.appendArray(val.Array())
+	case LogFieldValueSlice:
+		var err error
+		state := j.appendArrayBegin()
+		for _, elem := range val.Slice() {
+			if !j.appendElement() {
+				continue
+			}
+			err = errors.Join(err, j.appendValue(elem))
+		}
+		j.appendArrayEnd(state)
+		return err
+	case LogFieldValueMap:
+		// {"k":v,"k2":v2}
+		var err error
+		j.buffer.AppendByte(serializeJsonStart)
+		j.empty = true
+		for _, field := range val.Map() {
+			j.appendKey(field.Key)
+			err = errors.Join(err, j.appendValue(field.Value))
+		}
+		j.buffer.AppendByte(serializeJsonEnd)
+		j.empty = false
+		return err
+	case LogFieldValueNil:
+		j.buffer.AppendString("null")
+	case LogFieldValueBinary:
+		j.appendBinary(val.Binary())
+	case LogFieldValueByteString:
+		j.appendStringValue(string(val.ByteString()))
+	case LogFieldValueIP, LogFieldValuePrefix, LogFieldValueURL, LogFieldValueUUID, LogFieldValueHardwareAddr:
+		j.appendNetwork(val)
+	case LogFieldValueAny:
+		j.appendAny(val.Any())
+	default:
+		panic(fmt.Sprintf("Invalid FieldValueKind %s", val.Kind()))
+	}
+
+	return nil
+}
+
+// appendAny 写入任意类型 优先使用 json.Marshaler 失败时以字符串写入
+// 编码结果超出 MaxStringLength 时截断后以字符串写入 与文本格式一致
+func (j *jsonEncoder) appendAny(val any) {
+	if vv, ok := val.(json.Marshaler); ok {
+		data, err := vv.MarshalJSON()
+		if err == nil && json.Valid(data) {
+			j.appendJsonValue(data)
+			return
+		}
+		j.appendStringValue(fmt.Sprint(val))
+		return
+	}
+
+	buffer := bufferPool.Get()
+	defer buffer.Free()
+
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(val); err != nil {
+		j.appendStringValue(fmt.Sprint(val))
+		return
+	}
+	buffer.TrimNewLine()
+	j.appendJsonValue(buffer.Bytes())
+}
+
+// appendJsonValue 写入已编码的Json 超出最大长度时截断后以字符串写入
+func (j *jsonEncoder) appendJsonValue(data []byte) {
+	if maxLength := j.options.MaxStringLength; maxLength > 0 && len(data) > maxLength {
+		j.appendStringValue(string(data))
+		return
+	}
+	j.buffer.AppendBytes(data)
+}
+
+// appendStaticField 写入顶层静态字段 ,"k":v
+func (j *jsonEncoder) appendStaticField(field LogField) {
+	j.appendKey(field.Key)
+	if err := j.appendValue(field.Value); err != nil {
+		j.appendKey(field.Key + "Error")
+		j.appendString(err.Error())
+	}
+}
+
+// appendSeparator 容器内元素分隔 ,
+func (j *jsonEncoder) appendSeparator() {
+	if !j.empty {
+		j.buffer.AppendByte(serializeCommaStep)
+	}
+	j.empty = false
+}
+
+// appendKey 写入 "key":
+func (j *jsonEncoder) appendKey(key string) {
+	j.appendSeparator()
+	j.appendString(key)
+	j.buffer.AppendByte(serializeColonSplit)
+}
+
+// appendArrayBegin 数组开始 返回外层数组的写入状态
+func (j *jsonEncoder) appendArrayBegin() arrayState {
+	state := j.array
+	j.array = arrayState{}
+	j.buffer.AppendByte(serializeArrayBegin)
+	j.empty = true
+	return state
+}
+
+// appendArrayEnd 数组结束 写入被丢弃元素个数 并恢复外层数组的写入状态
+func (j *jsonEncoder) appendArrayEnd(state arrayState) {
+	if j.array.dropped > 0 {
+		j.appendSeparator()
+		j.appendString(fmt.Sprintf(truncatedElementsFormat, j.array.dropped))
+	}
+	j.array = state
+	j.buffer.AppendByte(serializeArrayEnd)
+	j.empty = false
+}
+
+// appendElement 数组元素开始 超出最大元素个数时丢弃并返回 false
+func (j *jsonEncoder) appendElement() bool {
+	if maxElements := j.options.MaxArrayElements; maxElements > 0 && j.array.elements >= maxElements {
+		j.array.dropped++
+		j.truncated = true
+		return false
+	}
+	j.array.elements++
+	j.appendSeparator()
+	return true
+}
+
+// enterContainer 进入嵌套容器 超出最大嵌套深度时写入标记并返回 false
+func (j *jsonEncoder) enterContainer() bool {
+	if maxDepth := j.options.MaxDepth; maxDepth > 0 && j.depth >= maxDepth {
+		j.appendString(truncatedDepthMarker)
+		j.truncated = true
+		return false
+	}
+	j.depth++
+	return true
+}
+
+// leaveContainer 离开嵌套容器
+func (j *jsonEncoder) leaveContainer() {
+	j.depth--
+}
+
+// appendObject 写入 {"k":v,"k2":v2}
+func (j *jsonEncoder) appendObject(val ObjectMarshaler) error {
+	if val == nil {
+		j.buffer.AppendString("null")
+		return nil
+	}
+	if !j.enterContainer() {
+		return nil
+	}
+	defer j.leaveContainer()
+	j.buffer.AppendByte(serializeJsonStart)
+	j.empty = true
+	err := val.MarshalLogObject(j)
+	j.buffer.AppendByte(serializeJsonEnd)
+	j.empty = false
+	return err
+}
+
+// appendArray 写入 [v,v2]
+func (j *jsonEncoder) appendArray(val ArrayMarshaler) error {
+	if val == nil {
+		j.buffer.AppendString("null")
+		return nil
+	}
+	if !j.enterContainer() {
+		return nil
+	}
+	defer j.leaveContainer()
+	state := j.appendArrayBegin()
+	err := val.MarshalLogArray(j)
+	j.appendArrayEnd(state)
+	return err
+}
+
+// appendFloat 写入浮点数 NaN/Inf 以字符串写入
+func (j *jsonEncoder) appendFloat(val float64) {
+	switch {
+	case math.IsNaN(val):
+		j.buffer.AppendString(`"NaN"`)
+	case math.IsInf(val, 1):
+		j.buffer.AppendString(`"+Inf"`)
+	case math.IsInf(val, -1):
+		j.buffer.AppendString(`"-Inf"`)
+	default:
+		j.buffer.AppendFloat(val, 64)
+	}
+}
+
+// appendError 写入错误 {"message":"msg","causes":[...],"stack":[...],"verbose":"..."}
+// causes 为 errors.Unwrap/errors.Join 展开后的错误链
+// stack 为 ErrorCallers 提供的调用栈 verbose 为实现 fmt.Formatter 的错误 %+v 的输出
+func (j *jsonEncoder) appendError(err error, depth int) {
+	if isNilError(err) {
+		j.buffer.AppendString("null")
+		return
+	}
+	j.buffer.AppendByte(serializeJsonStart)
+	j.empty = true
+	j.appendKey(errorMessageKey)
+	j.appendStringValue(err.Error())
+
+	// 错误链
+	if causes := errorCauses(err); len(causes) > 0 && depth < maxErrorCauseDepth {
+		j.appendKey(errorCausesKey)
+		state := j.appendArrayBegin()
+		for _, cause := range causes {
+			j.appendSeparator()
+			j.appendError(cause, depth+1)
+		}
+		j.appendArrayEnd(state)
+	}
+
+	if callers, ok := err.(ErrorCallers); ok {
+		// 调用栈
+		j.appendKey(errorStackKey)
+		state := j.appendArrayBegin()
+		frames := runtime.CallersFrames(callers.Callers())
+		for {
+			frame, more := frames.Next()
+			j.appendSeparator()
+			j.appendString(formatErrorFrame(frame))
+			if !more {
+				break
+			}
+		}
+		j.appendArrayEnd(state)
+	} else if verbose, ok := errorVerbose(err); ok {
+		// 详细信息
+		j.appendKey(errorVerboseKey)
+		j.appendStringValue(verbose)
+	}
+
+	j.buffer.AppendByte(serializeJsonEnd)
+	j.empty = false
+}
+
+// appendBinary 写入base64 超出最大长度截断
+func (j *jsonEncoder) appendBinary(val []byte) {
+	data, more := truncateBinary(val, j.options.MaxBinaryLength)
+	j.buffer.AppendByte(serializeStringMarks)
+	j.buffer.AppendString(base64.StdEncoding.EncodeToString(data))
+	if more > 0 {
+		j.buffer.AppendString(fmt.Sprintf(truncatedBytesFormat, more))
+		j.truncated = true
+	}
+	j.buffer.AppendByte(serializeStringMarks)
+}
+
+// appendNetwork 写入网络相关类型的标准格式 URL 需要转义
+func (j *jsonEncoder) appendNetwork(val LogFieldValue) {
+	if val.Kind() == LogFieldValueURL {
+		j.appendString(val.URL().String())
+		return
+	}
+	var data [64]byte
+	j.buffer.AppendByte(serializeStringMarks)
+	j.buffer.AppendBytes(appendNetworkValue(data[:0], val))
+	j.buffer.AppendByte(serializeStringMarks)
+}
+
+// appendTime 写入时间
+func (j *jsonEncoder) appendTime(val time.Time) {
+	j.options.encodeTime(val, (*jsonPrimitiveEncoder)(j))
+}
+
+// appendDuration 写入时间间隔 默认纳秒
+func (j *jsonEncoder) appendDuration(val time.Duration) {
+	encode := j.options.DurationEncoder
+	if encode == nil {
+		encode = NanosDurationEncoder
+	}
+	encode(val, (*jsonPrimitiveEncoder)(j))
+}
+
+// appendString 写入转义后的字符串
+func (j *jsonEncoder) appendString(val string) {
+	appendJsonString(j.buffer, val)
+}
+
+// appendStringValue 写入字符串字段值 超出最大长度截断
+func (j *jsonEncoder) appendStringValue(val string) {
+	str, more := truncateString(val, j.options.MaxStringLength)
+	if more > 0 {
+		str += fmt.Sprintf(truncatedBytesFormat, more)
+		j.truncated = true
+	}
+	j.appendString(str)
+}
+
+// appendJsonString 写入 "val" 按Json规范转义
+func appendJsonString(buffer *pool.Buffer, val string) {
+	buffer.AppendByte(serializeStringMarks)
+	start := 0
+	for i := 0; i < len(val); {
+		if b := val[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+			buffer.AppendString(val[start:i])
+			switch b {
+			case '"', '\\':
+				buffer.AppendByte('\\')
+				buffer.AppendByte(b)
+			case '\n':
+				buffer.AppendString(`\n`)
+			case '\r':
+				buffer.AppendString(`\r`)
+			case '\t':
+				buffer.AppendString(`\t`)
+			default:
+				buffer.AppendString(`\u00`)
+				buffer.AppendByte(jsonHex[b>>4])
+				buffer.AppendByte(jsonHex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(val[i:])
+		if r == utf8.RuneError && size == 1 {
+			// 非法 utf8
+			buffer.AppendString(val[start:i])
+			buffer.AppendString(`\ufffd`)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			buffer.AppendString(val[start:i])
+			buffer.AppendString(`\u202`)
+			buffer.AppendByte(jsonHex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buffer.AppendString(val[start:])
+	buffer.AppendByte(serializeStringMarks)
+}
+
+// AddString 实现 ObjectEncoder
+func (j *jsonEncoder) AddString(key, val string) {
+	j.appendKey(key)
+	j.appendStringValue(val)
+}
+
+// AddInt64 实现 ObjectEncoder
+func (j *jsonEncoder) AddInt64(key string, val int64) {
+	j.appendKey(key)
+	j.buffer.AppendInt(val)
+}
+
+// AddUint64 实现 ObjectEncoder
+func (j *jsonEncoder) AddUint64(key string, val uint64) {
+	j.appendKey(key)
+	j.buffer.AppendUint(val)
+}
+
+// AddFloat64 实现 ObjectEncoder
+func (j *jsonEncoder) AddFloat64(key string, val float64) {
+	j.appendKey(key)
+	j.appendFloat(val)
+}
+
+// AddBool 实现 ObjectEncoder
+func (j *jsonEncoder) AddBool(key string, val bool) {
+	j.appendKey(key)
+	j.buffer.AppendBool(val)
+}
+
+// AddTime 实现 ObjectEncoder
+func (j *jsonEncoder) AddTime(key string, val time.Time) {
+	j.appendKey(key)
+	j.appendTime(val)
+}
+
+// AddDuration 实现 ObjectEncoder
+func (j *jsonEncoder) AddDuration(key string, val time.Duration) {
+	j.appendKey(key)
+	j.appendDuration(val)
+}
+
+// AddObject 实现 ObjectEncoder
+func (j *jsonEncoder) AddObject(key string, val ObjectMarshaler) error {
+	j.appendKey(key)
+	return j.appendObject(val)
+}
+
+// AddArray 实现 ObjectEncoder
+func (j *jsonEncoder) AddArray(key string, val ArrayMarshaler) error {
+	j.appendKey(key)
+	return j.appendArray(val)
+}
+
+// AddAny 实现 ObjectEncoder
+func (j *jsonEncoder) AddAny(key string, val any) error {
+	j.appendKey(key)
+	return j.appendValue(AnyFieldValue(val))
+}
+
+// AppendString 实现 ArrayEncoder
+func (j *jsonEncoder) AppendString(val string) {
+	if !j.appendElement() {
+		return
+	}
+	j.appendStringValue(val)
+}
+
+// AppendInt64 实现 ArrayEncoder
+func (j *jsonEncoder) AppendInt64(val int64) {
+	if !j.appendElement() {
+		return
+	}
+	j.buffer.AppendInt(val)
+}
+
+// AppendUint64 实现 ArrayEncoder
+func (j *jsonEncoder) AppendUint64(val uint64) {
+	if !j.appendElement() {
+		return
+	}
+	j.buffer.AppendUint(val)
+}
+
+// AppendFloat64 实现 ArrayEncoder
+func (j *jsonEncoder) AppendFloat64(val float64) {
+	if !j.appendElement() {
+		return
+	}
+	j.appendFloat(val)
+}
+
+// AppendBool 实现 ArrayEncoder
+func (j *jsonEncoder) AppendBool(val bool) {
+	if !j.appendElement() {
+		return
+	}
+	j.buffer.AppendBool(val)
+}
+
+// AppendTime 实现 ArrayEncoder
+func (j *jsonEncoder) AppendTime(val time.Time) {
+	if !j.appendElement() {
+		return
+	}
+	j.appendTime(val)
+}
+
+// AppendDuration 实现 ArrayEncoder
+func (j *jsonEncoder) AppendDuration(val time.Duration) {
+	if !j.appendElement() {
+		return
+	}
+	j.appendDuration(val)
+}
+
+// AppendObject 实现 ArrayEncoder
+func (j *jsonEncoder) AppendObject(val ObjectMarshaler) error {
+	if !j.appendElement() {
+		return nil
+	}
+	return j.appendObject(val)
+}
+
+// AppendArray 实现 ArrayEncoder
+func (j *jsonEncoder) AppendArray(val ArrayMarshaler) error {
+	if !j.appendElement() {
+		return nil
+	}
+	return j.appendArray(val)
+}
+
+// AppendAny 实现 ArrayEncoder
+func (j *jsonEncoder) AppendAny(val any) error {
+	if !j.appendElement() {
+		return nil
+	}
+	return j.appendValue(AnyFieldValue(val))
+}
@@ -1,19 +1,58 @@
 package gslog
 
 import (
+	"encoding"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 )
 
 var (
-	errUnmarshalInvalid = errors.New("LogLevel: unmarshal invalid text to LogLeve")
+	// 检查 LogLevel 实现 encoding.TextMarshaler
+	_ encoding.TextMarshaler = (*LogLevel)(nil)
+	// 检查 LogLevel 实现 encoding.TextUnmarshaler
+	_ encoding.TextUnmarshaler = (*LogLevel)(nil)
+	// 检查 LogLevel 实现 json.Marshaler
+	_ json.Marshaler = (*LogLevel)(nil)
+	// 检查 LogLevel 实现 json.Unmarshaler
+	_ json.Unmarshaler = (*LogLevel)(nil)
+	// 检查 LogLevel 实现 flag.Value
+	_ flag.Value = (*LogLevel)(nil)
 )
 
+var (
+	errUnmarshalInvalid  = errors.New("LogLevel: unmarshal invalid text to LogLeve")
+	errRegisterEmptyName = errors.New("LogLevel: register empty level name")
+	errRegisterBuiltin   = errors.New("LogLevel: register conflicts with builtin level")
+	errRegisterDuplicate = errors.New("LogLevel: level or name already registered")
+)
+
+var (
+	// 自定义日志级别注册表
+	customLevelMutex  sync.RWMutex
+	customLevels      = make(map[LogLevel]*customLevelNames)
+	customLevelByName = make(map[string]LogLevel)
+)
+
+// customLevelNames 自定义日志级别名称 三种大小写形式
+type customLevelNames struct {
+	capital string
+	upCase  string
+	lowCase string
+}
+
 // LogLevel 日志级别
 type LogLevel int
 
+// 内置日志级别间隔 4 自定义日志级别可以位于两个内置级别之间
 const (
-	TraceLevel LogLevel = iota - 1
+	TraceLevel LogLevel = (iota - 2) * 4
 	DebugLevel
 	InfoLevel
 	WarnLevel
@@ -22,6 +61,72 @@ const (
 	FatalLevel
 )
 
+// RegisterLogLevel 注册自定义日志级别 例如 RegisterLogLevel(1, "notice") RegisterLogLevel(20, "audit")
+// 注册后 CapitalString/UpCaseString/LowCaseString 分别输出 Notice/NOTICE/notice
+// ParseLogLevel 以及 TextHandler/JsonHandler 均可识别
+// 内置日志级别为 Trace=-8 Debug=-4 Info=0 Warn=4 Error=8 Panic=12 Fatal=16 除这些数值外均可注册
+// 不允许覆盖内置日志级别以及重复注册
+func RegisterLogLevel(level LogLevel, name string) error {
+	if name == "" {
+		return errRegisterEmptyName
+	}
+	if level.builtin() {
+		return errRegisterBuiltin
+	}
+	var builtin LogLevel
+	if builtin.unmarshalBuiltinText([]byte(strings.ToLower(name))) {
+		return errRegisterBuiltin
+	}
+
+	lowCase := strings.ToLower(name)
+	names := &customLevelNames{
+		capital: capitalize(lowCase),
+		upCase:  strings.ToUpper(name),
+		lowCase: lowCase,
+	}
+
+	customLevelMutex.Lock()
+	defer customLevelMutex.Unlock()
+
+	if _, ok := customLevels[level]; ok {
+		return errRegisterDuplicate
+	}
+	if _, ok := customLevelByName[lowCase]; ok {
+		return errRegisterDuplicate
+	}
+	customLevels[level] = names
+	customLevelByName[lowCase] = level
+
+	return nil
+}
+
+// lookupCustomLevel 获取自定义日志级别名称
+func lookupCustomLevel(level LogLevel) (*customLevelNames, bool) {
+	customLevelMutex.RLock()
+	defer customLevelMutex.RUnlock()
+
+	names, ok := customLevels[level]
+	return names, ok
+}
+
+// lookupCustomLevelName 根据名称获取自定义日志级别 名称忽略大小写
+func lookupCustomLevelName(name string) (LogLevel, bool) {
+	customLevelMutex.RLock()
+	defer customLevelMutex.RUnlock()
+
+	level, ok := customLevelByName[strings.ToLower(name)]
+	return level, ok
+}
+
+// capitalize 首字母大写
+func capitalize(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	if r == utf8.RuneError {
+		return text
+	}
+	return string(unicode.ToUpper(r)) + text[size:]
+}
+
 // ParseLogLevel 解析日志级别字符串
 func ParseLogLevel(text string) (LogLevel, error) {
 	var lv LogLevel
@@ -30,17 +135,102 @@ func ParseLogLevel(text string) (LogLevel, error) {
 	return lv, err
 }
 
-// UnmarshalText 解析日志级别字符型并设置为对应日志级别
-func (l LogLevel) UnmarshalText(text []byte) error {
-	if !l.unmarshalText(text) && !l.unmarshalText(text) {
+// MarshalText 实现 encoding.TextMarshaler 输出小写日志级别名称 未知级别输出数值
+func (l LogLevel) MarshalText() ([]byte, error) {
+	if !l.known() {
+		return strconv.AppendInt(nil, int64(l), 10), nil
+	}
+	return []byte(l.LowCaseString()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 解析日志级别字符串并设置为对应日志级别
+// 支持任意大小写的日志级别名称 自定义日志级别以及数值
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	if l.unmarshalText(text) {
+		return nil
+	}
+	if l.unmarshalText([]byte(strings.ToLower(string(text)))) {
+		return nil
+	}
+	// 数值形式
+	num, err := strconv.Atoi(strings.TrimSpace(string(text)))
+	if err != nil {
+		return errUnmarshalInvalid
+	}
+	*l = LogLevel(num)
+
+	return nil
+}
+
+// MarshalJSON 实现 json.Marshaler 输出日志级别名称字符串
+func (l LogLevel) MarshalJSON() ([]byte, error) {
+	data, err := l.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(data))
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 支持字符串以及数值
+func (l *LogLevel) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return l.UnmarshalText([]byte(text))
+	}
+	var num int
+	if err := json.Unmarshal(data, &num); err != nil {
 		return errUnmarshalInvalid
 	}
+	*l = LogLevel(num)
 
 	return nil
 }
 
+// String 实现 fmt.Stringer 以及 flag.Value
+func (l LogLevel) String() string {
+	data, _ := l.MarshalText()
+	return string(data)
+}
+
+// Set 实现 flag.Value
+func (l *LogLevel) Set(text string) error {
+	return l.UnmarshalText([]byte(text))
+}
+
+// known 是否为内置或者已注册的日志级别
+func (l LogLevel) known() bool {
+	if l.builtin() {
+		return true
+	}
+	_, ok := lookupCustomLevel(l)
+	return ok
+}
+
+// builtin 是否为内置日志级别
+func (l LogLevel) builtin() bool {
+	switch l {
+	case TraceLevel, DebugLevel, InfoLevel, WarnLevel, ErrorLevel, PanicLevel, FatalLevel:
+		return true
+	default:
+		return false
+	}
+}
+
 // unmarshalText 私有化方法 解析字符串并设置为对应日志级别 返回解析是否成功
 func (l *LogLevel) unmarshalText(text []byte) bool {
+	if l.unmarshalBuiltinText(text) {
+		return true
+	}
+	// 自定义日志级别
+	level, ok := lookupCustomLevelName(string(text))
+	if ok {
+		*l = level
+	}
+	return ok
+}
+
+// unmarshalBuiltinText 解析内置日志级别字符串
+func (l *LogLevel) unmarshalBuiltinText(text []byte) bool {
 	switch string(text) {
 	case "trace", "TRACE":
 		*l = TraceLevel
@@ -80,6 +270,9 @@ func (l LogLevel) LowCaseString() string {
 	case FatalLevel:
 		return "fatal"
 	default:
+		if names, ok := lookupCustomLevel(l); ok {
+			return names.lowCase
+		}
 		return fmt.Sprintf("LogLevel({%d})", l)
 	}
 }
@@ -102,6 +295,9 @@ func (gs LogLevel) UpCaseString() string {
 	case FatalLevel:
 		return "FATAL"
 	default:
+		if names, ok := lookupCustomLevel(gs); ok {
+			return names.upCase
+		}
 		return fmt.Sprintf("LogLevel({%d})", gs)
 	}
 }
@@ -124,6 +320,70 @@ func (gs LogLevel) CapitalString() string {
 	case FatalLevel:
 		return "Fatal"
 	default:
+		if names, ok := lookupCustomLevel(gs); ok {
+			return names.capital
+		}
 		return fmt.Sprintf("LogLevel({%d})", gs)
 	}
 }
+
+// syslog severity RFC 5424
+const (
+	syslogAlert   = 1
+	syslogCrit    = 2
+	syslogErr     = 3
+	syslogWarning = 4
+	syslogNotice  = 5
+	syslogInfo    = 6
+	syslogDebug   = 7
+)
+
+// LevelEncoder 日志级别编码
+type LevelEncoder func(level LogLevel, enc PrimitiveEncoder)
+
+// LowercaseLevelEncoder 小写日志级别 debug/info/...
+func LowercaseLevelEncoder(level LogLevel, enc PrimitiveEncoder) {
+	enc.AppendString(level.LowCaseString())
+}
+
+// UppercaseLevelEncoder 大写日志级别 DEBUG/INFO/...
+func UppercaseLevelEncoder(level LogLevel, enc PrimitiveEncoder) {
+	enc.AppendString(level.UpCaseString())
+}
+
+// CapitalLevelEncoder 首字母大写日志级别 Debug/Info/...
+func CapitalLevelEncoder(level LogLevel, enc PrimitiveEncoder) {
+	enc.AppendString(level.CapitalString())
+}
+
+// NumericLevelEncoder 数值日志级别 Trace=-8 Debug=-4 Info=0 Warn=4 ...
+func NumericLevelEncoder(level LogLevel, enc PrimitiveEncoder) {
+	enc.AppendInt64(int64(level))
+}
+
+// SyslogLevelEncoder syslog severity 数值 自定义日志级别按所处区间映射
+// Trace/Debug=7 Info=6 Warn=4 Error=3 Panic=2 Fatal=1
+func SyslogLevelEncoder(level LogLevel, enc PrimitiveEncoder) {
+	enc.AppendInt64(int64(level.syslogSeverity()))
+}
+
+// syslogSeverity 日志级别对应的 syslog severity
+func (l LogLevel) syslogSeverity() int {
+	switch {
+	case l >= FatalLevel:
+		return syslogAlert
+	case l >= PanicLevel:
+		return syslogCrit
+	case l >= ErrorLevel:
+		return syslogErr
+	case l >= WarnLevel:
+		return syslogWarning
+	case l > InfoLevel:
+		// Info 与 Warn 之间的自定义级别
+		return syslogNotice
+	case l == InfoLevel:
+		return syslogInfo
+	default:
+		return syslogDebug
+	}
+}
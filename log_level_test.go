@@ -0,0 +1,381 @@
+package gslog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+// 测试注册的自定义日志级别 注册表为全局状态 各测试使用不同的数值与名称
+const (
+	testAuditLevel   LogLevel = 20
+	testVerboseLevel LogLevel = -12
+	testNoticeLevel  LogLevel = 1
+)
+
+func init() {
+	if err := RegisterLogLevel(testAuditLevel, "Audit"); err != nil {
+		panic(err)
+	}
+	if err := RegisterLogLevel(testNoticeLevel, "NOTICE"); err != nil {
+		panic(err)
+	}
+	if err := RegisterLogLevel(testVerboseLevel, "verbose"); err != nil {
+		panic(err)
+	}
+}
+
+func TestRegisterLogLevelErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		level LogLevel
+		text  string
+		want  error
+	}{
+		{name: "builtin info value", level: InfoLevel, text: "normal", want: errRegisterBuiltin},
+		{name: "builtin value", level: FatalLevel, text: "critical", want: errRegisterBuiltin},
+		{name: "builtin name", level: 20, text: "WARN", want: errRegisterBuiltin},
+		{name: "empty name", level: 21, text: "", want: errRegisterEmptyName},
+		{name: "duplicate level", level: testAuditLevel, text: "security", want: errRegisterDuplicate},
+		{name: "duplicate name", level: 22, text: "AUDIT", want: errRegisterDuplicate},
+		{name: "duplicate between builtin levels", level: testNoticeLevel, text: "info2", want: errRegisterDuplicate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterLogLevel(tt.level, tt.text); !errors.Is(err, tt.want) {
+				t.Fatalf("RegisterLogLevel(%d, %q) = %v, want %v", tt.level, tt.text, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomLogLevelNames(t *testing.T) {
+	tests := []struct {
+		level                    LogLevel
+		capital, upCase, lowCase string
+	}{
+		{level: testAuditLevel, capital: "Audit", upCase: "AUDIT", lowCase: "audit"},
+		{level: testVerboseLevel, capital: "Verbose", upCase: "VERBOSE", lowCase: "verbose"},
+		{level: testNoticeLevel, capital: "Notice", upCase: "NOTICE", lowCase: "notice"},
+	}
+	for _, tt := range tests {
+		if got := tt.level.CapitalString(); got != tt.capital {
+			t.Errorf("CapitalString(%d) = %q, want %q", tt.level, got, tt.capital)
+		}
+		if got := tt.level.UpCaseString(); got != tt.upCase {
+			t.Errorf("UpCaseString(%d) = %q, want %q", tt.level, got, tt.upCase)
+		}
+		if got := tt.level.LowCaseString(); got != tt.lowCase {
+			t.Errorf("LowCaseString(%d) = %q, want %q", tt.level, got, tt.lowCase)
+		}
+		for _, text := range []string{tt.capital, tt.upCase, tt.lowCase} {
+			if got, err := ParseLogLevel(text); err != nil || got != tt.level {
+				t.Errorf("ParseLogLevel(%q) = %d, %v, want %d", text, got, err, tt.level)
+			}
+		}
+	}
+}
+
+func TestCustomLogLevelOrdering(t *testing.T) {
+	// 自定义级别按数值参与级别过滤
+	if !(testVerboseLevel < TraceLevel && FatalLevel < testAuditLevel) {
+		t.Fatalf("unexpected ordering %d < %d < %d < %d", testVerboseLevel, TraceLevel, FatalLevel, testAuditLevel)
+	}
+
+	writer := &bufferWriteSyncer{}
+	logger := NewLogger(NewTextHandlerWithOptions(writer, WithLevel(ErrorLevel), WithTextFlag(LTextLogLevel)))
+	ctx := context.Background()
+	logger.Log(ctx, testVerboseLevel, "verbose dropped")
+	logger.Log(ctx, testAuditLevel, "audit kept")
+	logger.LogFields(ctx, testAuditLevel, "audit fields", String("user", "alice"))
+
+	want := []string{"[Audit] audit kept ", "[Audit] audit fields user=alice "}
+	if got := writer.Lines(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("lines = %q, want %q", got, want)
+	}
+
+	jsonWriter := &bufferWriteSyncer{}
+	jsonLogger := NewLogger(NewJsonHandlerWithOptions(jsonWriter, WithLevel(testVerboseLevel)))
+	jsonLogger.Log(ctx, testVerboseLevel, "verbose kept")
+	if !strings.Contains(jsonWriter.String(), `"level":"verbose"`) {
+		t.Fatalf("json output = %s", jsonWriter.String())
+	}
+}
+
+func TestCustomLogLevelBetweenBuiltin(t *testing.T) {
+	// NOTICE 位于 Info 与 Warn 之间
+	if !(InfoLevel < testNoticeLevel && testNoticeLevel < WarnLevel) {
+		t.Fatalf("unexpected ordering %d < %d < %d", InfoLevel, testNoticeLevel, WarnLevel)
+	}
+
+	ctx := context.Background()
+	tests := []struct {
+		level LogLevel
+		want  []string
+	}{
+		{level: InfoLevel, want: []string{"[Info] info ", "[Notice] notice ", "[Warn] warn "}},
+		{level: testNoticeLevel, want: []string{"[Notice] notice ", "[Warn] warn "}},
+		{level: WarnLevel, want: []string{"[Warn] warn "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			writer := &bufferWriteSyncer{}
+			logger := NewLogger(NewTextHandlerWithOptions(writer, WithLevel(tt.level), WithTextFlag(LTextLogLevel)))
+			logger.Info("info")
+			logger.Log(ctx, testNoticeLevel, "notice")
+			logger.Warn("warn")
+			if got := writer.Lines(); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("lines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	jsonWriter := &bufferWriteSyncer{}
+	NewLogger(NewJsonHandlerWithOptions(jsonWriter, WithLevelEncoder(UppercaseLevelEncoder))).Log(ctx, testNoticeLevel, "notice")
+	if !strings.Contains(jsonWriter.String(), `"level":"NOTICE"`) {
+		t.Errorf("json output = %s", jsonWriter.String())
+	}
+}
+
+func TestSyslogSeverityCustomLevels(t *testing.T) {
+	tests := []struct {
+		level LogLevel
+		want  int
+	}{
+		{level: testVerboseLevel, want: syslogDebug},
+		{level: TraceLevel, want: syslogDebug},
+		{level: DebugLevel, want: syslogDebug},
+		{level: InfoLevel, want: syslogInfo},
+		{level: testNoticeLevel, want: syslogNotice},
+		{level: WarnLevel, want: syslogWarning},
+		{level: ErrorLevel, want: syslogErr},
+		{level: PanicLevel, want: syslogCrit},
+		{level: FatalLevel, want: syslogAlert},
+		{level: testAuditLevel, want: syslogAlert},
+	}
+	for _, tt := range tests {
+		if got := tt.level.syslogSeverity(); got != tt.want {
+			t.Errorf("syslogSeverity(%d) = %d, want %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestLogLevelUnmarshalText(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    LogLevel
+		wantErr bool
+	}{
+		{text: "trace", want: TraceLevel},
+		{text: "DEBUG", want: DebugLevel},
+		{text: "Info", want: InfoLevel},
+		{text: "wArN", want: WarnLevel},
+		{text: "error", want: ErrorLevel},
+		{text: "Panic", want: PanicLevel},
+		{text: "FATAL", want: FatalLevel},
+		{text: "0", want: InfoLevel},
+		{text: "-8", want: TraceLevel},
+		{text: "notice", want: testNoticeLevel},
+		{text: "1", want: testNoticeLevel},
+		{text: " 42 ", want: 42},
+		{text: "audit", want: testAuditLevel},
+		{text: "AuDiT", want: testAuditLevel},
+		{text: "VERBOSE", want: testVerboseLevel},
+		{text: "", wantErr: true},
+		{text: "warning", wantErr: true},
+		{text: "1.5", wantErr: true},
+		{text: "info level", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			level := LogLevel(99)
+			err := level.UnmarshalText([]byte(tt.text))
+			if tt.wantErr {
+				if !errors.Is(err, errUnmarshalInvalid) {
+					t.Fatalf("UnmarshalText(%q) error = %v, want %v", tt.text, err, errUnmarshalInvalid)
+				}
+				return
+			}
+			if err != nil || level != tt.want {
+				t.Fatalf("UnmarshalText(%q) = %d, %v, want %d", tt.text, level, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogLevelRoundTrip(t *testing.T) {
+	levels := []LogLevel{TraceLevel, DebugLevel, InfoLevel, WarnLevel, ErrorLevel, PanicLevel, FatalLevel, testAuditLevel, testVerboseLevel, testNoticeLevel, 42}
+	for _, level := range levels {
+		t.Run(level.String(), func(t *testing.T) {
+			text, err := level.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fromText LogLevel
+			if err = fromText.UnmarshalText(text); err != nil || fromText != level {
+				t.Errorf("text round trip %q = %d, %v", text, fromText, err)
+			}
+
+			data, err := json.Marshal(level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fromJson LogLevel
+			if err = json.Unmarshal(data, &fromJson); err != nil || fromJson != level {
+				t.Errorf("json round trip %s = %d, %v", data, fromJson, err)
+			}
+
+			var fromFlag LogLevel
+			if err = fromFlag.Set(level.String()); err != nil || fromFlag != level {
+				t.Errorf("flag round trip %q = %d, %v", level.String(), fromFlag, err)
+			}
+		})
+	}
+}
+
+func TestLogLevelMarshal(t *testing.T) {
+	tests := []struct {
+		level LogLevel
+		text  string
+		json  string
+	}{
+		{level: InfoLevel, text: "info", json: `"info"`},
+		{level: FatalLevel, text: "fatal", json: `"fatal"`},
+		{level: testAuditLevel, text: "audit", json: `"audit"`},
+		{level: 42, text: "42", json: `"42"`},
+	}
+	for _, tt := range tests {
+		text, _ := tt.level.MarshalText()
+		data, _ := json.Marshal(tt.level)
+		if string(text) != tt.text || string(data) != tt.json {
+			t.Errorf("marshal %d = %q %s, want %q %s", tt.level, text, data, tt.text, tt.json)
+		}
+	}
+}
+
+func TestLogLevelUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    LogLevel
+		wantErr bool
+	}{
+		{data: `"WARN"`, want: WarnLevel},
+		{data: `"audit"`, want: testAuditLevel},
+		{data: `8`, want: ErrorLevel},
+		{data: `"8"`, want: ErrorLevel},
+		{data: `-12`, want: testVerboseLevel},
+		{data: `"nope"`, wantErr: true},
+		{data: `true`, wantErr: true},
+		{data: `1.5`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var level LogLevel
+		err := json.Unmarshal([]byte(tt.data), &level)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %d, want error", tt.data, level)
+			}
+			continue
+		}
+		if err != nil || level != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, %v, want %d", tt.data, level, err, tt.want)
+		}
+	}
+}
+
+func TestLogOptionsLevelFromConfig(t *testing.T) {
+	tests := []struct {
+		config string
+		want   LogLevel
+	}{
+		{config: `{"level":"warn"}`, want: WarnLevel},
+		{config: `{"level":"ERROR"}`, want: ErrorLevel},
+		{config: `{"level":-4}`, want: DebugLevel},
+		{config: `{"level":1}`, want: testNoticeLevel},
+		{config: `{"level":"audit"}`, want: testAuditLevel},
+	}
+	for _, tt := range tests {
+		var options LogOptions
+		if err := json.Unmarshal([]byte(tt.config), &options); err != nil {
+			t.Fatalf("Unmarshal(%s) error: %v", tt.config, err)
+		}
+		if options.Level != tt.want {
+			t.Errorf("Unmarshal(%s) level = %d, want %d", tt.config, options.Level, tt.want)
+		}
+	}
+
+	var options LogOptions
+	if err := json.Unmarshal([]byte(`{"level":"loud"}`), &options); err == nil {
+		t.Errorf("invalid level in config accepted")
+	}
+}
+
+func TestLogLevelFlag(t *testing.T) {
+	var options LogOptions
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&options.Level, "level", "log level")
+
+	if err := flags.Parse([]string{"-level", "Debug"}); err != nil {
+		t.Fatal(err)
+	}
+	if options.Level != DebugLevel {
+		t.Fatalf("flag level = %d, want %d", options.Level, DebugLevel)
+	}
+	if got := flags.Lookup("level").Value.String(); got != "debug" {
+		t.Fatalf("flag String() = %q, want debug", got)
+	}
+	if err := flags.Parse([]string{"-level", "AUDIT"}); err != nil || options.Level != testAuditLevel {
+		t.Fatalf("flag level = %d, %v, want %d", options.Level, err, testAuditLevel)
+	}
+
+	flags.SetOutput(new(strings.Builder))
+	if err := flags.Parse([]string{"-level", "loud"}); err == nil {
+		t.Fatalf("invalid flag level accepted")
+	}
+}
+
+func TestLevelEncoders(t *testing.T) {
+	levels := []LogLevel{TraceLevel, InfoLevel, testNoticeLevel, WarnLevel, FatalLevel, testAuditLevel}
+	tests := []struct {
+		name    string
+		encoder LevelEncoder
+		want    []string
+		// Json格式是否为数值
+		numeric bool
+	}{
+		{"lowercase", LowercaseLevelEncoder, []string{"trace", "info", "notice", "warn", "fatal", "audit"}, false},
+		{"uppercase", UppercaseLevelEncoder, []string{"TRACE", "INFO", "NOTICE", "WARN", "FATAL", "AUDIT"}, false},
+		{"capital", CapitalLevelEncoder, []string{"Trace", "Info", "Notice", "Warn", "Fatal", "Audit"}, false},
+		{"numeric", NumericLevelEncoder, []string{"-8", "0", "1", "4", "16", "20"}, true},
+		{"syslog", SyslogLevelEncoder, []string{"7", "6", "5", "4", "1", "1"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			textWriter, jsonWriter := &bufferWriteSyncer{}, &bufferWriteSyncer{}
+			opts := []Options{WithLevel(TraceLevel), WithTextFlag(LTextLogLevel), WithLevelEncoder(tt.encoder)}
+			textLogger := NewLogger(NewTextHandlerWithOptions(textWriter, opts...))
+			jsonLogger := NewLogger(NewJsonHandlerWithOptions(jsonWriter, opts...))
+			for _, level := range levels {
+				textLogger.Log(context.Background(), level, "m")
+				jsonLogger.Log(context.Background(), level, "m")
+			}
+
+			textLines, jsonLines := textWriter.Lines(), jsonWriter.Lines()
+			for idx, want := range tt.want {
+				if textLines[idx] != "["+want+"] m " {
+					t.Errorf("text %s = %q, want [%s]", levels[idx], textLines[idx], want)
+				}
+				jsonWant := `"level":"` + want + `"`
+				if tt.numeric {
+					jsonWant = `"level":` + want
+				}
+				if !strings.Contains(jsonLines[idx], jsonWant+`,"message":"m"`) {
+					t.Errorf("json %s = %s, want %s", levels[idx], jsonLines[idx], jsonWant)
+				}
+			}
+		})
+	}
+}
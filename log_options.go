@@ -5,8 +5,26 @@ var (
 	_ Options = (*optionFunc)(nil)
 )
 
+// BinaryEncoding 二进制字段文本格式编码方式
+type BinaryEncoding int
+
+const (
+	BinaryHex     BinaryEncoding = iota // 十六进制 默认
+	BinaryHexDump                       // hexdump -C 格式 行之间的换行转义为 \n
+	BinaryBase64                        // base64
+)
+
+// DuplicateKeyPolicy 重复key处理策略
+type DuplicateKeyPolicy int
+
+const (
+	DuplicateKeepAll   DuplicateKeyPolicy = iota // 保留全部 默认
+	DuplicateKeepFirst                           // 保留第一个
+	DuplicateKeepLast                            // 保留最后一个
+)
+
 type LogOptions struct {
-	// 输出日志等级
+	// 输出日志等级 零值为 InfoLevel
 	Level LogLevel `json:"level"`
 	// 日期输出格式
 	Layout string `json:"layout"`
@@ -22,6 +40,41 @@ type LogOptions struct {
 	LevelEncodeKey   string `json:"level_encode_key"`
 	MessageEncodeKey string `json:"message_encode_key"`
 	FieldEncodeKey   string `json:"field_encode_key"`
+
+	// 二进制字段文本格式编码方式 Json格式固定使用base64
+	BinaryEncoding BinaryEncoding `json:"binary_encoding"`
+	// 二进制字段最大输出长度 超出部分截断 0不限制
+	MaxBinaryLength int `json:"max_binary_length"`
+
+	// 大小限制 超出部分截断并输出 _truncated 字段 0不限制
+	// 消息最大长度 字节
+	MaxMessageLength int `json:"max_message_length"`
+	// 字符串字段 错误信息以及 Any 类型字段最大长度 字节
+	MaxStringLength int `json:"max_string_length"`
+	// 数组最大元素个数
+	MaxArrayElements int `json:"max_array_elements"`
+	// 字段最大嵌套深度
+	MaxDepth int `json:"max_depth"`
+	// 单条日志最大长度 字节 超出时丢弃全部字段并截断消息
+	MaxEntrySize int `json:"max_entry_size"`
+
+	// 时间编码 日志时间以及 Time 字段共用 未设置时按 Layout 格式化
+	TimeEncoder TimeEncoder `json:"-"`
+	// 时间间隔编码 未设置时文本格式输出 time.Duration.String() Json格式输出纳秒
+	DurationEncoder DurationEncoder `json:"-"`
+	// 日志级别编码 未设置时文本格式按 TextFlag 输出 Json格式输出小写
+	LevelEncoder LevelEncoder `json:"-"`
+
+	// 字段排列 同样作用于 Fields 字段组内部 静态字段与日志字段合并后统一去重排列 Json格式的静态字段仍位于顶层
+	// 重复key处理策略
+	DuplicateKeys DuplicateKeyPolicy `json:"duplicate_keys"`
+	// 字段按key字母序排序
+	SortFields bool `json:"sort_fields"`
+	// 优先输出的key 按给定顺序排在其余字段之前
+	LeadingKeys []string `json:"leading_keys"`
+
+	// 静态字段 每条日志都会输出 由 LogHandler 创建时预先编码
+	StaticFields []LogField `json:"-"`
 }
 
 // Options Option模式接口
@@ -65,6 +118,27 @@ func WithLayout(layout string) Options {
 	})
 }
 
+// WithTimeEncoder 设置时间编码 例如 RFC3339NanoTimeEncoder/UnixMilliTimeEncoder
+func WithTimeEncoder(encoder TimeEncoder) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.TimeEncoder = encoder
+	})
+}
+
+// WithDurationEncoder 设置时间间隔编码 例如 SecondsDurationEncoder/MillisDurationEncoder
+func WithDurationEncoder(encoder DurationEncoder) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.DurationEncoder = encoder
+	})
+}
+
+// WithLevelEncoder 设置日志级别编码 例如 UppercaseLevelEncoder/SyslogLevelEncoder
+func WithLevelEncoder(encoder LevelEncoder) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.LevelEncoder = encoder
+	})
+}
+
 // WithTimeEncodeKey 设置Json key
 func WithTimeEncodeKey(key string) Options {
 	return optionFunc(func(logOptions *LogOptions) {
@@ -99,3 +173,80 @@ func WithFieldEncodeKey(key string) Options {
 		logOptions.FieldEncodeKey = key
 	})
 }
+
+// WithBinaryEncoding 设置二进制字段文本格式编码方式
+func WithBinaryEncoding(encoding BinaryEncoding) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.BinaryEncoding = encoding
+	})
+}
+
+// WithMaxBinaryLength 设置二进制字段最大输出长度
+func WithMaxBinaryLength(length int) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.MaxBinaryLength = length
+	})
+}
+
+// WithMaxMessageLength 设置消息最大长度
+func WithMaxMessageLength(length int) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.MaxMessageLength = length
+	})
+}
+
+// WithMaxStringLength 设置字符串字段 错误信息以及 Any 类型字段最大长度
+func WithMaxStringLength(length int) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.MaxStringLength = length
+	})
+}
+
+// WithMaxArrayElements 设置数组最大元素个数
+func WithMaxArrayElements(count int) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.MaxArrayElements = count
+	})
+}
+
+// WithMaxDepth 设置字段最大嵌套深度
+func WithMaxDepth(depth int) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.MaxDepth = depth
+	})
+}
+
+// WithMaxEntrySize 设置单条日志最大长度
+func WithMaxEntrySize(size int) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.MaxEntrySize = size
+	})
+}
+
+// WithStaticFields 追加静态字段 例如 HostnameField/PidField/ServiceField
+func WithStaticFields(fields ...LogField) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.StaticFields = append(logOptions.StaticFields[:len(logOptions.StaticFields):len(logOptions.StaticFields)], fields...)
+	})
+}
+
+// WithDuplicateKeys 设置重复key处理策略
+func WithDuplicateKeys(policy DuplicateKeyPolicy) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.DuplicateKeys = policy
+	})
+}
+
+// WithSortFields 设置字段按key字母序排序
+func WithSortFields(sortFields bool) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.SortFields = sortFields
+	})
+}
+
+// WithLeadingKeys 设置优先输出的key 例如 request_id
+func WithLeadingKeys(keys ...string) Options {
+	return optionFunc(func(logOptions *LogOptions) {
+		logOptions.LeadingKeys = keys
+	})
+}
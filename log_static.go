@@ -0,0 +1,93 @@
+package gslog
+
+import (
+	"bytes"
+	"os"
+	"runtime/debug"
+
+	"gslog/internal/bufferPool"
+)
+
+const (
+	// 静态字段默认key
+	hostnameFieldKey    = "host"
+	pidFieldKey         = "pid"
+	serviceFieldKey     = "service"
+	environmentFieldKey = "env"
+	versionFieldKey     = "version"
+	revisionFieldKey    = "revision"
+	// 未知主机名
+	unknownHostname = "unknown"
+)
+
+// HostnameField 主机名字段 host=os.Hostname()
+func HostnameField() LogField {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = unknownHostname
+	}
+	return String(hostnameFieldKey, hostname)
+}
+
+// PidField 进程ID字段 pid=os.Getpid()
+func PidField() LogField {
+	return Int(pidFieldKey, os.Getpid())
+}
+
+// ServiceField 服务名字段 service=name
+func ServiceField(name string) LogField {
+	return String(serviceFieldKey, name)
+}
+
+// EnvironmentField 运行环境字段 env=environment
+func EnvironmentField(environment string) LogField {
+	return String(environmentFieldKey, environment)
+}
+
+// BuildInfoFields 构建信息字段 version=主模块版本 revision=VCS提交
+// 未包含构建信息或对应值为空时不输出该字段
+func BuildInfoFields() []LogField {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	var fields []LogField
+	if info.Main.Version != "" {
+		fields = append(fields, String(versionFieldKey, info.Main.Version))
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && setting.Value != "" {
+			fields = append(fields, String(revisionFieldKey, setting.Value))
+			break
+		}
+	}
+	return fields
+}
+
+// encodeTextStaticFields 预先编码文本格式静态字段 k=v k2=v2<space>
+func encodeTextStaticFields(fields []LogField, options *LogOptions) []byte {
+	buffer := bufferPool.Get()
+	defer buffer.Free()
+
+	encoder := newTextEncoder(buffer, options)
+	for _, field := range arrangeFields(fields, options) {
+		encoder.appendField(field)
+		buffer.AppendByte(serializeSpaceSplit)
+	}
+	return bytes.Clone(buffer.Bytes())
+}
+
+// encodeJsonStaticFields 预先编码Json格式静态字段 ,"k":v,"k2":v2
+func encodeJsonStaticFields(fields []LogField, options *LogOptions) []byte {
+	buffer := bufferPool.Get()
+	defer buffer.Free()
+
+	encoder := newJsonEncoder(buffer, options)
+	// 静态字段位于其余顶层key之后 需要前置分隔符
+	encoder.empty = false
+	for _, field := range arrangeFields(fields, options) {
+		encoder.appendStaticField(field)
+	}
+	return bytes.Clone(buffer.Bytes())
+}
@@ -0,0 +1,154 @@
+package gslog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+var (
+	// 检查 logWriter 实现 io.WriteCloser
+	_ io.WriteCloser = (*logWriter)(nil)
+)
+
+const (
+	// 未结束的行超过该长度时直接输出 避免无限缓存
+	maxPendingLineLength = 64 * 1024
+)
+
+// 查找调用方时跳过的包 标准库 log 以及 fmt 的格式化写入
+var callerSkipPrefixes = []string{"log.", "fmt."}
+
+// logWriter 将写入数据按行输出为日志
+type logWriter struct {
+	mutex  sync.Mutex
+	logger *Logger
+	level  LogLevel
+	// 未以换行结束的数据 等待后续写入或 Close
+	pending []byte
+}
+
+// Writer 返回 io.WriteCloser 写入数据按换行切分 每行输出一条 level 级别日志
+// 未以换行结束的数据会保留到下一次写入 Close 时输出剩余数据
+func (l *Logger) Writer(level LogLevel) io.WriteCloser {
+	return &logWriter{
+		logger: l,
+		level:  level,
+	}
+}
+
+// StdLogger 返回标准库 *log.Logger 输出的每行数据记录为一条 level 级别日志
+func (l *Logger) StdLogger(level LogLevel) *log.Logger {
+	return log.New(l.Writer(level), "", 0)
+}
+
+// RedirectStdLog 将标准库 log 包全局输出重定向到 logger 以 InfoLevel 记录
+// 返回恢复原有输出的方法
+func RedirectStdLog(logger *Logger) func() {
+	return RedirectStdLogAt(logger, InfoLevel)
+}
+
+// RedirectStdLogAt 将标准库 log 包全局输出重定向到 logger 以 level 记录
+// 返回恢复原有输出的方法
+func RedirectStdLogAt(logger *Logger, level LogLevel) func() {
+	flags := log.Flags()
+	prefix := log.Prefix()
+	writer := log.Writer()
+
+	// 时间与调用位置由 logger 输出
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(logger.Writer(level))
+
+	return func() {
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(writer)
+	}
+}
+
+// Write 实现 io.Writer 接口
+func (w *logWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	ctx := context.Background()
+	if !w.logger.Enabled(ctx, w.level) {
+		return n, nil
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	pc := callerPC()
+	for len(p) > 0 {
+		line, rest, found := bytes.Cut(p, []byte{serializeNewLine})
+		if !found {
+			// 保留未结束的行
+			w.pending = append(w.pending, line...)
+			if len(w.pending) >= maxPendingLineLength {
+				w.flush(ctx, pc)
+			}
+			break
+		}
+		if len(w.pending) > 0 {
+			line = append(w.pending, line...)
+			w.pending = w.pending[:0]
+		}
+		w.writeLine(ctx, pc, line)
+		p = rest
+	}
+
+	return n, nil
+}
+
+// Close 实现 io.Closer 接口 输出未以换行结束的剩余数据
+func (w *logWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.flush(context.Background(), callerPC())
+	return nil
+}
+
+// flush 输出缓存的未结束行
+func (w *logWriter) flush(ctx context.Context, pc uintptr) {
+	if len(w.pending) == 0 {
+		return
+	}
+	w.writeLine(ctx, pc, w.pending)
+	w.pending = w.pending[:0]
+}
+
+// writeLine 输出一行 忽略空行
+func (w *logWriter) writeLine(ctx context.Context, pc uintptr, line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	if len(line) == 0 {
+		return
+	}
+	w.logger.logFieldsPC(ctx, w.level, pc, string(line))
+}
+
+// callerPC 获取 Write 的实际调用方 跳过标准库 log/fmt 内部调用
+func callerPC() uintptr {
+	var pcs [16]uintptr
+	// runtime.Callers, this function, logWriter.Write
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		skip := false
+		for _, prefix := range callerSkipPrefixes {
+			if strings.HasPrefix(frame.Function, prefix) {
+				skip = true
+				break
+			}
+		}
+		if !skip || !more {
+			// Frame.PC 为调用指令位置 LogEntry.Source 按返回地址解析
+			return frame.PC + 1
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package gslog
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLoggerWriterPartialLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w io.Writer)
+		want  []string
+	}{
+		{
+			name: "io.Copy one byte per write",
+			write: func(w io.Writer) {
+				_, _ = io.Copy(w, iotest.OneByteReader(strings.NewReader("first line\nsecond line\n")))
+			},
+			want: []string{"first line", "second line"},
+		},
+		{
+			name: "bufio flush in the middle of a line",
+			write: func(w io.Writer) {
+				buffered := bufio.NewWriterSize(w, 16)
+				_, _ = buffered.WriteString("a line longer than the buffer\r\nshort\n")
+				_ = buffered.Flush()
+			},
+			want: []string{"a line longer than the buffer", "short"},
+		},
+		{
+			name: "empty lines skipped",
+			write: func(w io.Writer) {
+				_, _ = w.Write([]byte("\n\nx\n\n"))
+			},
+			want: []string{"x"},
+		},
+		{
+			name: "trailing data kept until close",
+			write: func(w io.Writer) {
+				_, _ = w.Write([]byte("done\npart"))
+				_, _ = w.Write([]byte("ial"))
+			},
+			want: []string{"done"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bufferWriteSyncer{}
+			logger := NewLogger(NewTextHandlerWithOptions(writer, WithLevel(InfoLevel)))
+			tt.write(logger.Writer(InfoLevel))
+
+			if got := writer.Lines(); !equalMessages(got, tt.want) {
+				t.Fatalf("messages = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggerWriterClose(t *testing.T) {
+	writer := &bufferWriteSyncer{}
+	logger := NewLogger(NewTextHandlerWithOptions(writer, WithLevel(InfoLevel)))
+
+	w := logger.Writer(InfoLevel)
+	_, _ = w.Write([]byte("no newline"))
+	if lines := writer.Lines(); len(lines) != 0 {
+		t.Fatalf("partial line written before Close: %q", lines)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := writer.Lines(); !equalMessages(got, []string{"no newline"}) {
+		t.Fatalf("messages after Close = %q", got)
+	}
+	// 重复 Close 不会重复输出
+	_ = w.Close()
+	if got := writer.Lines(); len(got) != 1 {
+		t.Fatalf("messages after second Close = %q", got)
+	}
+}
+
+func TestStdLoggerSource(t *testing.T) {
+	writer := &bufferWriteSyncer{}
+	logger := NewLogger(NewTextHandlerWithOptions(writer, WithLevel(InfoLevel), WithTextFlag(LTextFile)))
+
+	line := currentLine() + 1
+	logger.StdLogger(WarnLevel).Printf("from %s", "std")
+
+	restore := RedirectStdLog(logger)
+	redirectLine := currentLine() + 1
+	log.Print("redirected")
+	restore()
+
+	lines := writer.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("lines = %q", lines)
+	}
+	if !strings.Contains(lines[0], "log_std_test.go:"+strconv.Itoa(line)+" from std") {
+		t.Errorf("std logger line = %q, want caller line %d", lines[0], line)
+	}
+	if !strings.Contains(lines[1], "log_std_test.go:"+strconv.Itoa(redirectLine)+" redirected") {
+		t.Errorf("redirected line = %q, want caller line %d", lines[1], redirectLine)
+	}
+}
+
+// equalMessages 文本日志行(未设置 TextFlag 时为 "msg ")与期望消息是否一致
+func equalMessages(lines, messages []string) bool {
+	if len(lines) != len(messages) {
+		return false
+	}
+	for idx := range lines {
+		if strings.TrimSuffix(lines[idx], " ") != messages[idx] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,727 @@
+package gslog
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gslog/pool"
+)
+
+// textEncoder 文本格式编码器 TextHandler 以及 LogField.MarshalText 共用
+type textEncoder struct {
+	buffer  *pool.Buffer
+	options *LogOptions
+	// 当前容器是否尚未写入元素
+	empty bool
+	// 当前嵌套深度
+	depth int
+	// 合并为 key.k=v 的嵌套 LogField 层级
+	merged int
+	// 当前数组写入状态
+	array arrayState
+	// 是否发生截断
+	truncated bool
+}
+
+// newTextEncoder 实例化文本编码器 options 允许为 nil
+func newTextEncoder(buffer *pool.Buffer, options *LogOptions) *textEncoder {
+	if options == nil {
+		options = &LogOptions{}
+	}
+	return &textEncoder{
+		buffer:  buffer,
+		options: options,
+		empty:   true,
+	}
+}
+
+// appendField 写入 key=value 嵌套 LogField 写入 key.k=v
+func (t *textEncoder) appendField(field LogField) {
+	if field.Value.Kind() == LogFieldValueField {
+		// 合并为完整 key 错误附加字段使用同一前缀 合并的层级同样计入最大嵌套深度
+		if t.depthExceeded() {
+			t.buffer.AppendString(field.Key)
+			t.buffer.AppendByte(serializeFieldStep)
+			t.appendTextValue(truncatedDepthMarker)
+			t.truncated = true
+			return
+		}
+		inner := field.Value.Field()
+		inner.Key = field.Key + string(serializeRadixPointSplit) + inner.Key
+		t.merged++
+		t.appendField(inner)
+		t.merged--
+		return
+	}
+	t.buffer.AppendString(field.Key)
+	t.buffer.AppendByte(serializeFieldStep)
+	val := field.Value.Resolve()
+	if err := t.appendValue(val); err != nil {
+		// key=value keyError=err
+		t.buffer.AppendByte(serializeSpaceSplit)
+		t.buffer.AppendString(field.Key)
+		t.buffer.AppendString("Error")
+		t.buffer.AppendByte(serializeFieldStep)
+		t.buffer.AppendString(err.Error())
+	}
+	if val.Kind() == LogFieldValueError {
+		t.appendErrorDetail(field.Key, val.Error())
+	}
+}
+
+// appendFieldList 写入 [k=v, k2=v2]
+func (t *textEncoder) appendFieldList(fields []LogField) {
+	fields = arrangeFields(fields, t.options)
+	t.buffer.AppendByte(serializeArrayBegin)
+	t.empty = true
+	for _, field := range fields {
+		t.appendSeparator()
+		t.appendField(field)
+	}
+	t.buffer.AppendByte(serializeArrayEnd)
+	t.empty = false
+}
+
+// appendValue 写入字段值
+func (t *textEncoder) appendValue(val LogFieldValue) error {
+	// 确认输出后才解析 LogValuer
+	val = val.Resolve()
+	if nestedKind(val.Kind()) {
+		if !t.enterContainer() {
+			return nil
+		}
+		defer t.leaveContainer()
+	}
+	switch val.Kind() {
+	case LogFieldValueInt64:
+		t.buffer.AppendInt(val.Int64())
+	case LogFieldValueUint64:
+		t.buffer.AppendUint(val.Uint64())
+	case LogFieldValueFloat64:
+		t.buffer.AppendFloat(val.Float64(), 64)
+	case LogFieldValueBool:
+		t.buffer.AppendBool(val.Bool())
+	case LogFieldValueString:
+		t.appendString(val.String())
+	case LogFieldValueTime:
+		t.appendTime(val.Time())
+	case LogFieldValueDuration:
+		t.appendDuration(val.Duration())
+	case LogFieldValueError:
+		t.appendError(val.Error())
+	case LogFieldValueInt64s:
+		state := t.appendArrayBegin()
+		for _, num := range val.Int64s() {
+			t.AppendInt64(num)
+		}
+		t.appendArrayEnd(state)
+	case LogFieldValueUint64s:
+		state := t.appendArrayBegin()
+		for _, num := range val.Uint64s() {
+			t.AppendUint64(num)
+		}
+		t.appendArrayEnd(state)
+	case LogFieldValueFloat64s:
+		state := t.appendArrayBegin()
+		for _, num := range val.Float64s() {
+			t.AppendFloat64(num)
+		}
+		t.appendArrayEnd(state)
+	case LogFieldValueStrings:
+		state := t.appendArrayBegin()
+		for _, str := range val.Strings() {
+			t.AppendString(str)
+		}
+		t.appendArrayEnd(state)
+	case LogFieldValueBools:
+		state := t.appendArrayBegin()
+		for _, boolVal := range val.Bools() {
+			t.AppendBool(boolVal)
+		}
+		t.appendArrayEnd(state)
+	case LogFieldValueField:
+		// {k=v}
+		t.buffer.AppendByte(serializeJsonStart)
+		t.appendField(val.Field())
+		t.buffer.AppendByte(serializeJsonEnd)
+		t.empty = false
+	case LogFieldValueFields:
+		t.appendFieldList(val.Fields())
+	case LogFieldValueObject:
+		return t.appendObject(val.Object())
+	case LogFieldValueArray:
+		return t.appendArray(val.Array())
+	case LogFieldValueSlice:
+		var err error
+		state := t.appendArrayBegin()
+		for _, elem := range val.Slice() {
+			if !t.appendElement() {
+				continue
+			}
+			err = errors.Join(err, t.appendValue(elem))
+		}
+		t.appendArrayEnd(state)
+		return err
+	case LogFieldValueMap:
+		// {k=v, k2=v2}
+		t.buffer.AppendByte(serializeJsonStart)
+		t.empty = true
+		for _, field := range val.Map() {
+			t.appendSeparator()
+			t.appendField(field)
+		}
+		t.buffer.AppendByte(serializeJsonEnd)
+		t.empty = false
+	case LogFieldValueNil:
+		t.buffer.AppendString("<nil>")
+	case LogFieldValueBinary:
+		t.appendBinary(val.Binary())
+	case LogFieldValueByteString:
+		t.appendString(string(val.ByteString()))
+	case LogFieldValueIP, LogFieldValuePrefix, LogFieldValueURL, LogFieldValueUUID, LogFieldValueHardwareAddr:
+		t.appendNetwork(val)
+	case LogFieldValueAny:
+		// 值是 any 类型调用 尝试调用 encoding.TextMarshaler
+		if vv, ok := val.Any().(encoding.TextMarshaler); ok {
+			data, err := vv.MarshalText()
+			if err != nil {
+				return err
+			}
+			t.appendString(string(data))
+			return nil
+		}
+		t.appendString(fmt.Sprint(val.Any()))
+	default:
+		panic(fmt.Sprintf("Invalid FieldValueKind %s", val.Kind()))
+	}
+
+	return nil
+}
+
+// appendSeparator 容器内元素分隔 ", "
+func (t *textEncoder) appendSeparator() {
+	if !t.empty {
+		t.buffer.AppendByte(serializeCommaStep)
+		t.buffer.AppendByte(serializeSpaceSplit)
+	}
+	t.empty = false
+}
+
+// appendKey 写入 key=
+func (t *textEncoder) appendKey(key string) {
+	t.appendSeparator()
+	t.buffer.AppendString(key)
+	t.buffer.AppendByte(serializeFieldStep)
+}
+
+// appendArrayBegin 数组开始 返回外层数组的写入状态
+func (t *textEncoder) appendArrayBegin() arrayState {
+	state := t.array
+	t.array = arrayState{}
+	t.buffer.AppendByte(serializeArrayBegin)
+	t.empty = true
+	return state
+}
+
+// appendArrayEnd 数组结束 写入被丢弃元素个数 并恢复外层数组的写入状态
+func (t *textEncoder) appendArrayEnd(state arrayState) {
+	if t.array.dropped > 0 {
+		t.appendSeparator()
+		t.buffer.AppendString(fmt.Sprintf(truncatedElementsFormat, t.array.dropped))
+	}
+	t.array = state
+	t.buffer.AppendByte(serializeArrayEnd)
+	t.empty = false
+}
+
+// appendElement 数组元素开始 超出最大元素个数时丢弃并返回 false
+func (t *textEncoder) appendElement() bool {
+	if maxElements := t.options.MaxArrayElements; maxElements > 0 && t.array.elements >= maxElements {
+		t.array.dropped++
+		t.truncated = true
+		return false
+	}
+	t.array.elements++
+	t.appendSeparator()
+	return true
+}
+
+// enterContainer 进入嵌套容器 超出最大嵌套深度时写入标记并返回 false
+func (t *textEncoder) enterContainer() bool {
+	if t.depthExceeded() {
+		t.buffer.AppendString(truncatedDepthMarker)
+		t.truncated = true
+		return false
+	}
+	t.depth++
+	return true
+}
+
+// depthExceeded 是否已达到最大嵌套深度 合并为 key.k=v 的层级同样计入
+func (t *textEncoder) depthExceeded() bool {
+	maxDepth := t.options.MaxDepth
+	return maxDepth > 0 && t.depth+t.merged >= maxDepth
+}
+
+// leaveContainer 离开嵌套容器
+func (t *textEncoder) leaveContainer() {
+	t.depth--
+}
+
+// appendObject 写入 {k=v, k2=v2}
+func (t *textEncoder) appendObject(val ObjectMarshaler) error {
+	if val == nil {
+		t.buffer.AppendString("<nil>")
+		return nil
+	}
+	if !t.enterContainer() {
+		return nil
+	}
+	defer t.leaveContainer()
+	t.buffer.AppendByte(serializeJsonStart)
+	t.empty = true
+	err := val.MarshalLogObject(t)
+	t.buffer.AppendByte(serializeJsonEnd)
+	t.empty = false
+	return err
+}
+
+// appendArray 写入 [v, v2]
+func (t *textEncoder) appendArray(val ArrayMarshaler) error {
+	if val == nil {
+		t.buffer.AppendString("<nil>")
+		return nil
+	}
+	if !t.enterContainer() {
+		return nil
+	}
+	defer t.leaveContainer()
+	state := t.appendArrayBegin()
+	err := val.MarshalLogArray(t)
+	t.appendArrayEnd(state)
+	return err
+}
+
+// appendError 写入 err: msg 多个错误写入 err: [msg1, msg2] 包含换行等的错误信息加引号
+func (t *textEncoder) appendError(err error) {
+	t.buffer.AppendString(textErrorPrefix)
+	if isNilError(err) {
+		t.buffer.AppendString("<nil>")
+		return
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		state := t.appendArrayBegin()
+		for _, cause := range joined.Unwrap() {
+			t.appendSeparator()
+			if isNilError(cause) {
+				t.buffer.AppendString("<nil>")
+				continue
+			}
+			t.buffer.AppendString(textErrorString(t.truncateString(cause.Error())))
+		}
+		t.appendArrayEnd(state)
+		return
+	}
+	t.buffer.AppendString(textErrorString(t.truncateString(err.Error())))
+}
+
+// appendErrorDetail 错误值之后追加 key.causes=[...] 以及 key.stack=[...] 或 key.verbose=...
+func (t *textEncoder) appendErrorDetail(key string, err error) {
+	if isNilError(err) {
+		return
+	}
+	// 错误链 errors.Join 的直接子错误已在错误值中输出 只追加更深层的错误
+	var causes []string
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, cause := range joined.Unwrap() {
+			causes = t.collectErrorCauses(causes, cause, 1)
+		}
+	} else {
+		causes = t.collectErrorCauses(causes, err, 0)
+	}
+	if len(causes) > 0 {
+		t.appendErrorDetailKey(key, errorCausesKey)
+		t.buffer.AppendByte(serializeArrayBegin)
+		t.buffer.AppendString(strings.Join(causes, serializeListSplit))
+		t.buffer.AppendByte(serializeArrayEnd)
+	}
+
+	if callers, ok := errorCallers(err); ok {
+		t.appendErrorDetailKey(key, errorStackKey)
+		t.buffer.AppendByte(serializeArrayBegin)
+		frames := runtime.CallersFrames(callers.Callers())
+		for first := true; ; first = false {
+			frame, more := frames.Next()
+			if !first {
+				t.buffer.AppendString(serializeListSplit)
+			}
+			t.buffer.AppendString(formatErrorFrame(frame))
+			if !more {
+				break
+			}
+		}
+		t.buffer.AppendByte(serializeArrayEnd)
+	} else if verbose, ok := errorVerbose(err); ok {
+		t.appendErrorDetailKey(key, errorVerboseKey)
+		t.buffer.AppendString(textErrorString(t.truncateString(verbose)))
+	}
+}
+
+// appendErrorDetailKey 写入错误附加字段的 key 顶层使用空格分隔 字段组内使用逗号分隔
+func (t *textEncoder) appendErrorDetailKey(key, detail string) {
+	if t.depth > 0 {
+		t.buffer.AppendString(serializeListSplit)
+	} else {
+		t.buffer.AppendByte(serializeSpaceSplit)
+	}
+	t.buffer.AppendString(key)
+	t.buffer.AppendByte(serializeRadixPointSplit)
+	t.buffer.AppendString(detail)
+	t.buffer.AppendByte(serializeFieldStep)
+}
+
+// textErrorString 错误信息包含换行 key= 容器符号或元素分隔符时加引号 保持单行且可被解析
+func textErrorString(msg string) string {
+	if textMessageNeedsQuote(msg) || strings.ContainsAny(msg, "[]{}") || strings.Contains(msg, serializeListSplit) {
+		return strconv.Quote(msg)
+	}
+	return msg
+}
+
+// collectErrorCauses 深度优先收集错误链中各层错误信息 超出最大长度截断
+func (t *textEncoder) collectErrorCauses(causes []string, err error, depth int) []string {
+	if depth >= maxErrorCauseDepth || isNilError(err) {
+		return causes
+	}
+	for _, cause := range errorCauses(err) {
+		if isNilError(cause) {
+			continue
+		}
+		causes = append(causes, textErrorString(t.truncateString(cause.Error())))
+		causes = t.collectErrorCauses(causes, cause, depth+1)
+	}
+	return causes
+}
+
+// appendBinary 按配置写入 hex/hexdump/base64 超出最大长度截断
+func (t *textEncoder) appendBinary(val []byte) {
+	data, more := truncateBinary(val, t.options.MaxBinaryLength)
+	var str string
+	switch t.options.BinaryEncoding {
+	case BinaryHexDump:
+		// 行之间的换行转义为 \n 保持日志单行
+		str = strings.ReplaceAll(strings.TrimSuffix(hex.Dump(data), "\n"), "\n", `\n`)
+	case BinaryBase64:
+		str = base64.StdEncoding.EncodeToString(data)
+	default:
+		str = hex.EncodeToString(data)
+	}
+	if more > 0 {
+		str += fmt.Sprintf(truncatedBytesFormat, more)
+		t.truncated = true
+	}
+	// 纯数字的 hex 以及 hexdump 右侧的字符列可能被解析为数字或字段
+	t.appendTextValue(str)
+}
+
+// appendNetwork 写入网络相关类型的标准格式
+func (t *textEncoder) appendNetwork(val LogFieldValue) {
+	var data [64]byte
+	t.buffer.AppendBytes(appendNetworkValue(data[:0], val))
+}
+
+// appendString 写入字符串 超出最大长度截断
+func (t *textEncoder) appendString(val string) {
+	t.appendTextValue(t.truncateString(val))
+}
+
+// truncateString 字符串超出 MaxStringLength 时截断并追加截断标记
+func (t *textEncoder) truncateString(val string) string {
+	str, more := truncateString(val, t.options.MaxStringLength)
+	if more > 0 {
+		str += fmt.Sprintf(truncatedBytesFormat, more)
+		t.truncated = true
+	}
+	return str
+}
+
+// appendTextValue 写入字符串形式的值 会被 TextDecoder 解析为字段 容器或其他类型时加引号
+func (t *textEncoder) appendTextValue(str string) {
+	// 容器内的空字符串需要与空容器区分
+	if (str == "" && t.depth > 0) || textValueNeedsQuote(str, t.options.layout()) {
+		t.buffer.AppendString(strconv.Quote(str))
+		return
+	}
+	t.buffer.AppendString(str)
+}
+
+// textMessageNeedsQuote 消息以引号开头 包含换行或包含 key= 时需要加引号 否则无法与字段区分
+func textMessageNeedsQuote(val string) bool {
+	if val == "" {
+		return false
+	}
+	return val[0] == serializeStringMarks || strings.ContainsAny(val, "\r\n") ||
+		(strings.IndexByte(val, serializeFieldStep) >= 0 && nextTextKey(val, 0, false) >= 0)
+}
+
+// textValueNeedsQuote 字符串字段值除消息的规则外 包含容器符号 元素分隔符
+// 或会被解析为 nil/bool/错误/数字/时间间隔/时间时需要加引号
+func textValueNeedsQuote(val, layout string) bool {
+	if val == "" {
+		return false
+	}
+	if textMessageNeedsQuote(val) || strings.ContainsAny(val, "[]{}") || strings.Contains(val, serializeListSplit) {
+		return true
+	}
+	switch {
+	case val == "<nil>" || val == "true" || val == "false" || val == "NaN" || val == "+Inf" || val == "-Inf" ||
+		strings.HasPrefix(val, textErrorPrefix):
+		return true
+	case !strings.ContainsAny(val, "0123456789"):
+		// 数字 时间间隔以及时间都包含数字
+		return false
+	}
+	// 先按字符判断 避免普通字符串解析失败时的开销
+	if textNumberShape(val) {
+		if _, ok := decodeNumber(val); ok {
+			return true
+		}
+	}
+	if textDurationShape(val) {
+		if _, err := time.ParseDuration(val); err == nil {
+			return true
+		}
+	}
+	if textTimeShape(val, layout) {
+		_, err := time.ParseInLocation(layout, val, time.Local)
+		return err == nil
+	}
+	return false
+}
+
+// textNumberShape 是否可能为数字 可选符号后为十进制数字或 0x 开头的十六进制浮点数
+func textNumberShape(val string) bool {
+	digits := val
+	if digits[0] == '+' || digits[0] == '-' {
+		digits = digits[1:]
+	}
+	charset := "0123456789.eE+-_"
+	if len(digits) > 2 && digits[0] == '0' && (digits[1] == 'x' || digits[1] == 'X') {
+		digits, charset = digits[2:], "0123456789abcdefABCDEF.pP+-_"
+	}
+	if digits == "" {
+		return false
+	}
+	for idx := 0; idx < len(digits); idx++ {
+		if strings.IndexByte(charset, digits[idx]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// textDurationShape 是否可能为时间间隔 可选符号后只包含数字 小数点以及单位 ns/us/µs/ms/s/m/h
+func textDurationShape(val string) bool {
+	if val[0] == '+' || val[0] == '-' {
+		val = val[1:]
+	}
+	if val == "" || !isTextDigit(val[0]) && val[0] != '.' {
+		return false
+	}
+	for idx := 0; idx < len(val); idx++ {
+		// µ 以及 μ 为多字节字符
+		if val[idx] < utf8.RuneSelf && strings.IndexByte("0123456789.nsumh", val[idx]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// textTimeShape 是否可能为 layout 格式的时间
+// layout 以数字开头时首字符须为数字 layout 不包含字母时不能包含字母
+func textTimeShape(val, layout string) bool {
+	if layout == "" {
+		return true
+	}
+	if isTextDigit(layout[0]) && !isTextDigit(val[0]) {
+		return false
+	}
+	return hasTextLetter(layout) || !hasTextLetter(val)
+}
+
+// isTextDigit 是否为数字
+func isTextDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+// hasTextLetter 是否包含 ASCII 字母
+func hasTextLetter(val string) bool {
+	for idx := 0; idx < len(val); idx++ {
+		if ch := val[idx] | 0x20; 'a' <= ch && ch <= 'z' {
+			return true
+		}
+	}
+	return false
+}
+
+// appendTime 写入时间
+func (t *textEncoder) appendTime(val time.Time) {
+	t.options.encodeTime(val, (*textPrimitiveEncoder)(t))
+}
+
+// appendDuration 写入时间间隔 默认 time.Duration.String()
+func (t *textEncoder) appendDuration(val time.Duration) {
+	encode := t.options.DurationEncoder
+	if encode == nil {
+		encode = StringDurationEncoder
+	}
+	encode(val, (*textPrimitiveEncoder)(t))
+}
+
+// AddString 实现 ObjectEncoder
+func (t *textEncoder) AddString(key, val string) {
+	t.appendKey(key)
+	t.appendString(val)
+}
+
+// AddInt64 实现 ObjectEncoder
+func (t *textEncoder) AddInt64(key string, val int64) {
+	t.appendKey(key)
+	t.buffer.AppendInt(val)
+}
+
+// AddUint64 实现 ObjectEncoder
+func (t *textEncoder) AddUint64(key string, val uint64) {
+	t.appendKey(key)
+	t.buffer.AppendUint(val)
+}
+
+// AddFloat64 实现 ObjectEncoder
+func (t *textEncoder) AddFloat64(key string, val float64) {
+	t.appendKey(key)
+	t.buffer.AppendFloat(val, 64)
+}
+
+// AddBool 实现 ObjectEncoder
+func (t *textEncoder) AddBool(key string, val bool) {
+	t.appendKey(key)
+	t.buffer.AppendBool(val)
+}
+
+// AddTime 实现 ObjectEncoder
+func (t *textEncoder) AddTime(key string, val time.Time) {
+	t.appendKey(key)
+	t.appendTime(val)
+}
+
+// AddDuration 实现 ObjectEncoder
+func (t *textEncoder) AddDuration(key string, val time.Duration) {
+	t.appendKey(key)
+	t.appendDuration(val)
+}
+
+// AddObject 实现 ObjectEncoder
+func (t *textEncoder) AddObject(key string, val ObjectMarshaler) error {
+	t.appendKey(key)
+	return t.appendObject(val)
+}
+
+// AddArray 实现 ObjectEncoder
+func (t *textEncoder) AddArray(key string, val ArrayMarshaler) error {
+	t.appendKey(key)
+	return t.appendArray(val)
+}
+
+// AddAny 实现 ObjectEncoder
+func (t *textEncoder) AddAny(key string, val any) error {
+	t.appendKey(key)
+	return t.appendValue(AnyFieldValue(val))
+}
+
+// AppendString 实现 ArrayEncoder
+func (t *textEncoder) AppendString(val string) {
+	if !t.appendElement() {
+		return
+	}
+	t.appendString(val)
+}
+
+// AppendInt64 实现 ArrayEncoder
+func (t *textEncoder) AppendInt64(val int64) {
+	if !t.appendElement() {
+		return
+	}
+	t.buffer.AppendInt(val)
+}
+
+// AppendUint64 实现 ArrayEncoder
+func (t *textEncoder) AppendUint64(val uint64) {
+	if !t.appendElement() {
+		return
+	}
+	t.buffer.AppendUint(val)
+}
+
+// AppendFloat64 实现 ArrayEncoder
+func (t *textEncoder) AppendFloat64(val float64) {
+	if !t.appendElement() {
+		return
+	}
+	t.buffer.AppendFloat(val, 64)
+}
+
+// AppendBool 实现 ArrayEncoder
+func (t *textEncoder) AppendBool(val bool) {
+	if !t.appendElement() {
+		return
+	}
+	t.buffer.AppendBool(val)
+}
+
+// AppendTime 实现 ArrayEncoder
+func (t *textEncoder) AppendTime(val time.Time) {
+	if !t.appendElement() {
+		return
+	}
+	t.appendTime(val)
+}
+
+// AppendDuration 实现 ArrayEncoder
+func (t *textEncoder) AppendDuration(val time.Duration) {
+	if !t.appendElement() {
+		return
+	}
+	t.appendDuration(val)
+}
+
+// AppendObject 实现 ArrayEncoder
+func (t *textEncoder) AppendObject(val ObjectMarshaler) error {
+	if !t.appendElement() {
+		return nil
+	}
+	return t.appendObject(val)
+}
+
+// AppendArray 实现 ArrayEncoder
+func (t *textEncoder) AppendArray(val ArrayMarshaler) error {
+	if !t.appendElement() {
+		return nil
+	}
+	return t.appendArray(val)
+}
+
+// AppendAny 实现 ArrayEncoder
+func (t *textEncoder) AppendAny(val any) error {
+	if !t.appendElement() {
+		return nil
+	}
+	return t.appendValue(AnyFieldValue(val))
+}
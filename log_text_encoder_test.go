@@ -0,0 +1,77 @@
+package gslog
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTextHandlerBinaryHexDump(t *testing.T) {
+	writer := &bufferWriteSyncer{}
+	logger := NewLogger(NewTextHandlerWithOptions(writer, WithBinaryEncoding(BinaryHexDump)))
+
+	data := []byte("0123456789abcdef0123456789abcdef!")
+	logger.LogFields(context.Background(), InfoLevel, "dump", Binary("data", data), String("next", "v"))
+
+	lines := writer.Lines()
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1: %q", len(lines), writer.String())
+	}
+	want := "data=" + strings.ReplaceAll(strings.TrimSuffix(hex.Dump(data), "\n"), "\n", `\n`) + " next=v"
+	if !strings.Contains(lines[0], want) {
+		t.Errorf("line = %q, want %q", lines[0], want)
+	}
+}
+
+func BenchmarkTextHandlerStringFields(b *testing.B) {
+	values := []struct {
+		name string
+		val  string
+	}{
+		{"plain", "hello world"},
+		{"id", "order-12345-abc"},
+		{"version", "v1.2.3"},
+		{"leading digit", "12345-abc"},
+		{"number", "12345"},
+	}
+	for _, tt := range values {
+		b.Run(tt.name, func(b *testing.B) {
+			logger := NewLogger(NewTextHandlerWithOptions(discardWriteSyncer{}, WithTextFlag(DefaultLTextFlag)))
+			ctx := context.Background()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.LogFields(ctx, InfoLevel, "bench", String("a", tt.val), String("b", tt.val), String("c", tt.val))
+			}
+		})
+	}
+}
+
+func TestTextValueNeedsQuote(t *testing.T) {
+	// 字符判断只用于跳过解析 结果须与逐一解析一致
+	parsed := func(val, layout string) bool {
+		if _, ok := decodeNumber(val); ok {
+			return true
+		}
+		if _, err := time.ParseDuration(val); err == nil {
+			return true
+		}
+		_, err := time.ParseInLocation(layout, val, time.Local)
+		return err == nil
+	}
+	values := []string{
+		"0", "12345", "-7", "+7", "3.14", ".5", "1e5", "-1.5E-3", "0x1p-2", "0X1.8P+1", "1_000", "0x1_0p0",
+		"1s", "-3h", "1h30m", "1.5µs", "2μs", "100ns", "5ms", ".5m",
+		"2024/06/11 10:00:00.000000", "2024-06-11T10:00:00Z", "10:00:00",
+		"order-12345-abc", "v1.2.3", "12345-abc", "1.2.3", "0xZZ", "2024", "12h-ago", "x1", "1+", "e5",
+	}
+	layouts := []string{DefaultTimeLayout, time.RFC3339, time.Kitchen, time.ANSIC, time.TimeOnly}
+	for _, layout := range layouts {
+		for _, val := range values {
+			if want := parsed(val, layout); textValueNeedsQuote(val, layout) != want {
+				t.Errorf("textValueNeedsQuote(%q, %q) = %v, want %v", val, layout, !want, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package gslog
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// ISO8601 UTC 时间格式
+	iso8601UTCLayout = "2006-01-02T15:04:05.000Z"
+)
+
+// TimeEncoder 时间编码 日志时间以及所有 Time 字段共用
+type TimeEncoder func(val time.Time, enc PrimitiveEncoder)
+
+// LayoutTimeEncoder 按 layout 格式化时间
+func LayoutTimeEncoder(layout string) TimeEncoder {
+	return func(val time.Time, enc PrimitiveEncoder) {
+		enc.AppendString(val.Format(layout))
+	}
+}
+
+// RFC3339NanoTimeEncoder 按 RFC3339Nano 格式化时间
+func RFC3339NanoTimeEncoder(val time.Time, enc PrimitiveEncoder) {
+	enc.AppendString(val.Format(time.RFC3339Nano))
+}
+
+// ISO8601UTCTimeEncoder 转换为 UTC 并按 ISO8601 毫秒精度格式化时间 2006-01-02T15:04:05.000Z
+func ISO8601UTCTimeEncoder(val time.Time, enc PrimitiveEncoder) {
+	enc.AppendString(val.UTC().Format(iso8601UTCLayout))
+}
+
+// UnixTimeEncoder 以 Unix 秒数输出时间
+func UnixTimeEncoder(val time.Time, enc PrimitiveEncoder) {
+	enc.AppendInt64(val.Unix())
+}
+
+// UnixMilliTimeEncoder 以 Unix 毫秒数输出时间
+func UnixMilliTimeEncoder(val time.Time, enc PrimitiveEncoder) {
+	enc.AppendInt64(val.UnixMilli())
+}
+
+// UnixNanoTimeEncoder 以 Unix 纳秒数输出时间
+func UnixNanoTimeEncoder(val time.Time, enc PrimitiveEncoder) {
+	enc.AppendInt64(val.UnixNano())
+}
+
+// CachedLayoutTimeEncoder 按 layout 格式化时间 同一秒内复用已格式化的日期部分 只重新计算小数秒
+// layout 的小数秒需要使用固定位数的 .000/,000 形式 .999 形式的 layout 每次都会完整格式化
+func CachedLayoutTimeEncoder(layout string) TimeEncoder {
+	cached := newCachedTimeLayout(layout)
+	return cached.encode
+}
+
+// cachedTimeLayout 按秒缓存格式化结果
+type cachedTimeLayout struct {
+	layout string
+	// 小数秒之前以及之后的 layout
+	prefix string
+	suffix string
+	// 小数秒位数 包含分隔符 . 或 ,
+	fraction string
+	// 是否可以缓存
+	cacheable bool
+	// 最近一秒的格式化结果
+	last atomic.Pointer[cachedTimeSecond]
+}
+
+// cachedTimeSecond 某一秒的格式化结果
+type cachedTimeSecond struct {
+	unix     int64
+	location *time.Location
+	prefix   string
+	suffix   string
+}
+
+// newCachedTimeLayout 拆分 layout 中的小数秒
+func newCachedTimeLayout(layout string) *cachedTimeLayout {
+	cached := &cachedTimeLayout{layout: layout, prefix: layout, cacheable: true}
+	for idx := 0; idx+1 < len(layout); idx++ {
+		if layout[idx] != '.' && layout[idx] != ',' {
+			continue
+		}
+		end := idx + 1
+		for end < len(layout) && (layout[end] == '0' || layout[end] == '9') {
+			end++
+		}
+		// 小数秒必须紧跟秒 05
+		if end == idx+1 || idx < 2 || layout[idx-2:idx] != "05" {
+			continue
+		}
+		if strings.Contains(layout[idx+1:end], "9") {
+			cached.cacheable = false
+			return cached
+		}
+		cached.prefix = layout[:idx]
+		cached.fraction = layout[idx:end]
+		cached.suffix = layout[end:]
+		return cached
+	}
+	return cached
+}
+
+// encode 实现 TimeEncoder
+func (c *cachedTimeLayout) encode(val time.Time, enc PrimitiveEncoder) {
+	if !c.cacheable {
+		enc.AppendString(val.Format(c.layout))
+		return
+	}
+	last := c.last.Load()
+	if last == nil || last.unix != val.Unix() || last.location != val.Location() {
+		second := val.Truncate(time.Second)
+		last = &cachedTimeSecond{
+			unix:     val.Unix(),
+			location: val.Location(),
+			prefix:   second.Format(c.prefix),
+			suffix:   second.Format(c.suffix),
+		}
+		c.last.Store(last)
+	}
+	if c.fraction == "" {
+		enc.AppendString(last.prefix)
+		return
+	}
+
+	// 小数秒 按位数补零
+	var data [64]byte
+	buf := append(data[:0], last.prefix...)
+	buf = append(buf, c.fraction[0])
+	digits := len(c.fraction) - 1
+	nanos := strconv.Itoa(val.Nanosecond() + 1e9)[1:]
+	buf = append(buf, nanos[:min(digits, len(nanos))]...)
+	buf = append(buf, last.suffix...)
+	enc.AppendString(string(buf))
+}
+
+// encodeTime 按配置编码时间 未配置 TimeEncoder 时按 Layout 格式化
+func (l *LogOptions) encodeTime(val time.Time, enc PrimitiveEncoder) {
+	if l.TimeEncoder != nil {
+		l.TimeEncoder(val, enc)
+		return
+	}
+	enc.AppendString(val.Format(l.layout()))
+}
+
+// layout 时间格式 未配置时使用 DefaultTimeLayout
+func (l *LogOptions) layout() string {
+	if l.Layout == "" {
+		return DefaultTimeLayout
+	}
+	return l.Layout
+}
+
+// DurationEncoder 时间间隔编码 所有 Duration 字段共用
+type DurationEncoder func(val time.Duration, enc PrimitiveEncoder)
+
+// StringDurationEncoder 以 time.Duration.String() 输出时间间隔 例如 1.5s
+func StringDurationEncoder(val time.Duration, enc PrimitiveEncoder) {
+	enc.AppendString(val.String())
+}
+
+// SecondsDurationEncoder 以浮点秒数输出时间间隔
+func SecondsDurationEncoder(val time.Duration, enc PrimitiveEncoder) {
+	enc.AppendFloat64(val.Seconds())
+}
+
+// MillisDurationEncoder 以整数毫秒数输出时间间隔
+func MillisDurationEncoder(val time.Duration, enc PrimitiveEncoder) {
+	enc.AppendInt64(val.Milliseconds())
+}
+
+// NanosDurationEncoder 以整数纳秒数输出时间间隔
+func NanosDurationEncoder(val time.Duration, enc PrimitiveEncoder) {
+	enc.AppendInt64(int64(val))
+}
@@ -0,0 +1,207 @@
+package gslog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordPrimitiveEncoder 记录写入的值
+type recordPrimitiveEncoder struct {
+	values []any
+}
+
+func (r *recordPrimitiveEncoder) AppendString(val string) {
+	r.values = append(r.values, val)
+}
+
+func (r *recordPrimitiveEncoder) AppendInt64(val int64) {
+	r.values = append(r.values, val)
+}
+
+func (r *recordPrimitiveEncoder) AppendFloat64(val float64) {
+	r.values = append(r.values, val)
+}
+
+// fixedClock 固定时间的 Clock
+type fixedClock time.Time
+
+func (f fixedClock) Now() time.Time {
+	return time.Time(f)
+}
+
+// encodeOne 编码单个值并返回写入的值
+func encodeOne(t *testing.T, encode func(enc PrimitiveEncoder)) any {
+	t.Helper()
+
+	enc := &recordPrimitiveEncoder{}
+	encode(enc)
+	if len(enc.values) != 1 {
+		t.Fatalf("encoder wrote %d values, want 1: %v", len(enc.values), enc.values)
+	}
+	return enc.values[0]
+}
+
+func TestTimeEncoders(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	tm := time.Date(2024, 5, 6, 7, 8, 9, 123456789, shanghai)
+	tests := []struct {
+		name    string
+		encoder TimeEncoder
+		want    any
+	}{
+		{"layout", LayoutTimeEncoder("15:04:05.000"), "07:08:09.123"},
+		{"rfc3339nano", RFC3339NanoTimeEncoder, "2024-05-06T07:08:09.123456789+08:00"},
+		{"iso8601 utc", ISO8601UTCTimeEncoder, "2024-05-05T23:08:09.123Z"},
+		{"unix", UnixTimeEncoder, tm.Unix()},
+		{"unix milli", UnixMilliTimeEncoder, tm.UnixMilli()},
+		{"unix nano", UnixNanoTimeEncoder, tm.UnixNano()},
+		{"cached layout", CachedLayoutTimeEncoder("2006/01/02 15:04:05.000000"), "2024/05/06 07:08:09.123456"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encodeOne(t, func(enc PrimitiveEncoder) { tt.encoder(tm, enc) }); got != tt.want {
+				t.Errorf("encoded %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeEncoderHandlers(t *testing.T) {
+	tm := time.Date(2024, 5, 6, 7, 8, 9, 500000000, time.UTC)
+	tests := []struct {
+		name    string
+		encoder TimeEncoder
+		text    string
+		json    string
+	}{
+		{"default", nil, "at=2024/05/06 07:08:09.500000", `"at":"2024/05/06 07:08:09.500000"`},
+		{"iso8601 utc", ISO8601UTCTimeEncoder, "at=2024-05-06T07:08:09.500Z", `"at":"2024-05-06T07:08:09.500Z"`},
+		{"unix milli", UnixMilliTimeEncoder, fmt.Sprintf("at=%d", tm.UnixMilli()), fmt.Sprintf(`"at":%d`, tm.UnixMilli())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			textWriter, jsonWriter := &bufferWriteSyncer{}, &bufferWriteSyncer{}
+			clock := fixedClock(tm)
+			opts := []Options{WithTextFlag(LTextTime), WithTimeEncoder(tt.encoder)}
+			NewLogger(NewTextHandlerWithOptions(textWriter, opts...)).WithClock(clock).Info("msg", Time("at", tm))
+			NewLogger(NewJsonHandlerWithOptions(jsonWriter, opts...)).WithClock(clock).Info("msg", Time("at", tm))
+
+			// 日志时间与 Time 字段使用同一个编码器
+			textTime := strings.TrimPrefix(tt.text, "at=")
+			if want := textTime + " msg " + tt.text + " \n"; textWriter.String() != want {
+				t.Errorf("text output %q, want %q", textWriter.String(), want)
+			}
+			jsonTime := strings.TrimPrefix(tt.json, `"at":`)
+			if got := jsonWriter.String(); !strings.HasPrefix(got, `{"time":`+jsonTime+",") || !strings.Contains(got, "{"+tt.json+"}") {
+				t.Errorf("json output %s, want time %s and field %s", got, jsonTime, tt.json)
+			}
+		})
+	}
+}
+
+func TestCachedLayoutTimeEncoder(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	base := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	// 同一秒内不同小数秒 跨秒 跨分钟 时区变化 1970 年之前
+	times := []time.Time{
+		base,
+		base.Add(123456789),
+		base.Add(999999999),
+		base.Add(time.Second),
+		base.Add(time.Second + 1),
+		base.Add(51 * time.Second),
+		base.Add(51 * time.Second).In(shanghai),
+		base.Add(51*time.Second + 7).In(shanghai),
+		base.Add(51*time.Second + 7),
+		time.Date(1969, 12, 31, 23, 59, 59, 999000000, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 1000000, time.UTC),
+	}
+	layouts := []string{
+		DefaultTimeLayout,
+		"2006-01-02T15:04:05.000Z07:00",
+		"2006-01-02 15:04:05,000 MST",
+		"15:04:05.000000000",
+		"15:04:05",
+		"15:04:05.999",
+		"Jan _2 15:04:05.00 2006",
+	}
+	for _, layout := range layouts {
+		t.Run(layout, func(t *testing.T) {
+			encode := CachedLayoutTimeEncoder(layout)
+			for _, tm := range times {
+				got := encodeOne(t, func(enc PrimitiveEncoder) { encode(tm, enc) })
+				if want := tm.Format(layout); got != want {
+					t.Errorf("encode(%s) = %q, want %q", tm.Format(time.RFC3339Nano), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCachedLayoutTimeEncoderConcurrent(t *testing.T) {
+	encode := CachedLayoutTimeEncoder(DefaultTimeLayout)
+	base := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := 0; idx < 1000; idx++ {
+				// 不同协程交替写入不同秒 缓存频繁失效
+				tm := base.Add(time.Duration(worker)*time.Second + time.Duration(idx)*time.Microsecond)
+				enc := &recordPrimitiveEncoder{}
+				encode(tm, enc)
+				if want := tm.Format(DefaultTimeLayout); enc.values[0] != want {
+					t.Errorf("encode = %v, want %s", enc.values[0], want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDurationEncoders(t *testing.T) {
+	val := 1750 * time.Millisecond
+	tests := []struct {
+		name    string
+		encoder DurationEncoder
+		want    any
+		text    string
+		json    string
+	}{
+		{"default", nil, nil, "d=1.75s", `{"d":1750000000}`},
+		{"string", StringDurationEncoder, "1.75s", "d=1.75s", `{"d":"1.75s"}`},
+		{"seconds", SecondsDurationEncoder, 1.75, "d=1.75", `{"d":1.75}`},
+		{"millis", MillisDurationEncoder, int64(1750), "d=1750", `{"d":1750}`},
+		{"nanos", NanosDurationEncoder, int64(1750000000), "d=1750000000", `{"d":1750000000}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.encoder != nil {
+				if got := encodeOne(t, func(enc PrimitiveEncoder) { tt.encoder(val, enc) }); got != tt.want {
+					t.Errorf("encoded %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+				}
+			}
+
+			textWriter, jsonWriter := &bufferWriteSyncer{}, &bufferWriteSyncer{}
+			opts := []Options{WithTextFlag(0), WithDurationEncoder(tt.encoder)}
+			NewLogger(NewTextHandlerWithOptions(textWriter, opts...)).Info("msg", Duration("d", val), Any("ds", []time.Duration{val}))
+			NewLogger(NewJsonHandlerWithOptions(jsonWriter, opts...)).Info("msg", Duration("d", val), Any("ds", []time.Duration{val}))
+
+			// 切片元素使用同一个编码器
+			elem := strings.TrimPrefix(tt.text, "d=")
+			if want := "msg " + tt.text + " ds=[" + elem + "] \n"; textWriter.String() != want {
+				t.Errorf("text output %q, want %q", textWriter.String(), want)
+			}
+			jsonElem := strings.TrimSuffix(strings.TrimPrefix(tt.json, `{"d":`), "}")
+			if want := `"fields":[` + tt.json + `,{"ds":[` + jsonElem + `]}]}` + "\n"; !strings.HasSuffix(jsonWriter.String(), want) {
+				t.Errorf("json output %s, want ...%s", jsonWriter.String(), want)
+			}
+		})
+	}
+}
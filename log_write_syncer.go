@@ -78,6 +78,8 @@ type LogFileRollover struct {
 	// 是否执行压缩
 	// 压缩后会被添加 .gz 后缀
 	Compress bool
+	// 时间来源 用于备份文件命名以及 MaxAge 过期判断 nil 使用系统时钟
+	Clock Clock
 	// 源文件 通过日志分割器的日志会被追加到该文件
 	// 如果初始长度超出 MaxSize 会被切割并重命名加上当前时间信息
 	// 然后会使用原始文件名创建一个新日志文件
@@ -229,7 +231,7 @@ func (l *LogFileRollover) openNew() error {
 	if err == nil {
 		// 老文件存在 改名 保持Mode不变
 		mode = info.Mode()
-		newName := utils.GetBackupNameByTime(name, backupTimeFormat)
+		newName := utils.GetBackupNameByTime(name, backupTimeFormat, clockNow(l.Clock))
 		if err = os.Rename(name, newName); err != nil {
 			return err
 		}
@@ -344,7 +346,7 @@ func (l *LogFileRollover) rolloverExec() error {
 		remained := make([]*LogFileMeta, 0)
 		// 截止时间
 		diff := time.Duration(int64(24*l.MaxAge) * int64(time.Hour))
-		cutOffTime := time.Now().Add(-1 * diff)
+		cutOffTime := clockNow(l.Clock).Add(-1 * diff)
 		for _, logFileMeta := range logFileMetas {
 			// 早于截止时间 删除
 			if logFileMeta.Time.Before(cutOffTime) {
@@ -429,7 +431,7 @@ func (l *LogFileRollover) loadFileList() ([]*LogFileMeta, error) {
 			})
 			continue
 		}
-		if fileTime, err := utils.TimeFromFileName(fileInfo.Name(), prefix, ext, ext+compressSuffix); err == nil {
+		if fileTime, err := utils.TimeFromFileName(fileInfo.Name(), prefix, ext+compressSuffix, backupTimeFormat); err == nil {
 			// 已经压缩
 			logFileMetas = append(logFileMetas, &LogFileMeta{
 				Time:     fileTime,
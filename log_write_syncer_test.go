@@ -0,0 +1,46 @@
+package gslog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gslog/internal/utils"
+)
+
+func TestLogFileRolloverLoadFileList(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+	older := time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local)
+	newer := older.Add(time.Hour)
+
+	files := []string{
+		name,
+		utils.GetBackupNameByTime(name, backupTimeFormat, older) + compressSuffix,
+		utils.GetBackupNameByTime(name, backupTimeFormat, newer),
+		filepath.Join(dir, "other.log"),
+		filepath.Join(dir, "app_invalid.log"),
+	}
+	for _, file := range files {
+		if err := os.WriteFile(file, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rollover := NewLogFileRollover(name, 1, 0, 0, false)
+	metas, err := rollover.loadFileList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metas) != 2 {
+		t.Fatalf("loadFileList found %d backups, want 2", len(metas))
+	}
+	// 按时间从新到旧 压缩备份同样被识别
+	if !metas[0].Time.Equal(newer) || filepath.Ext(metas[0].FileInfo.Name()) != ".log" {
+		t.Errorf("metas[0] = %s %v, want plain backup at %v", metas[0].FileInfo.Name(), metas[0].Time, newer)
+	}
+	if !metas[1].Time.Equal(older) || filepath.Ext(metas[1].FileInfo.Name()) != compressSuffix {
+		t.Errorf("metas[1] = %s %v, want gzip backup at %v", metas[1].FileInfo.Name(), metas[1].Time, older)
+	}
+}
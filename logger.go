@@ -2,9 +2,9 @@ package gslog
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"runtime"
-	"time"
 )
 
 var (
@@ -15,6 +15,12 @@ var (
 // Logger 日志器
 type Logger struct {
 	handler LogHandler
+	// 日志器公共字段 每条日志都会携带
+	fields []LogField
+	// 日志时间来源 nil 使用系统时钟
+	clock Clock
+	// key/value 参数不合法时的处理方式
+	args argsPolicy
 }
 
 // NewLogger 实例化日志器
@@ -24,6 +30,44 @@ func NewLogger(handler LogHandler) *Logger {
 	}
 }
 
+// With 返回携带公共字段的子日志器 子日志器与父日志器共用同一个 LogHandler
+func (l *Logger) With(fields ...LogField) *Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	child := *l
+	child.fields = make([]LogField, 0, len(l.fields)+len(fields))
+	child.fields = append(child.fields, l.fields...)
+	child.fields = append(child.fields, fields...)
+
+	return &child
+}
+
+// WithClock 返回使用指定时钟的子日志器 子日志器与父日志器共用同一个 LogHandler
+func (l *Logger) WithClock(clock Clock) *Logger {
+	child := *l
+	child.clock = clock
+
+	return &child
+}
+
+// WithArgsMode 返回使用指定 key/value 参数处理方式的子日志器 子日志器与父日志器共用同一个 LogHandler
+func (l *Logger) WithArgsMode(mode ArgsMode) *Logger {
+	child := *l
+	child.args.mode = mode
+
+	return &child
+}
+
+// WithInternalErrorHook 返回使用指定内部错误回调的子日志器 ArgsStrict 通过回调上报非法参数
+// nil 输出到 os.Stderr 子日志器与父日志器共用同一个 LogHandler
+func (l *Logger) WithInternalErrorHook(hook func(err error)) *Logger {
+	child := *l
+	child.args.hook = hook
+
+	return &child
+}
+
 // Trace 格式化输出 TraceLevel 级别日志
 func (l *Logger) Trace(msg string, args ...any) {
 	l.log(context.Background(), TraceLevel, msg, args...)
@@ -94,11 +138,92 @@ func (l *Logger) FatalContext(ctx context.Context, msg string, args ...any) {
 	l.log(ctx, FatalLevel, msg, args...)
 }
 
+// Tracef 以 fmt.Sprintf 格式化输出 TraceLevel 级别日志 仅在需要输出时格式化
+func (l *Logger) Tracef(format string, args ...any) {
+	l.logf(context.Background(), TraceLevel, format, args...)
+}
+
+// Debugf 以 fmt.Sprintf 格式化输出 DebugLevel 级别日志 仅在需要输出时格式化
+func (l *Logger) Debugf(format string, args ...any) {
+	l.logf(context.Background(), DebugLevel, format, args...)
+}
+
+// Infof 以 fmt.Sprintf 格式化输出 InfoLevel 级别日志 仅在需要输出时格式化
+func (l *Logger) Infof(format string, args ...any) {
+	l.logf(context.Background(), InfoLevel, format, args...)
+}
+
+// Warnf 以 fmt.Sprintf 格式化输出 WarnLevel 级别日志 仅在需要输出时格式化
+func (l *Logger) Warnf(format string, args ...any) {
+	l.logf(context.Background(), WarnLevel, format, args...)
+}
+
+// Errorf 以 fmt.Sprintf 格式化输出 ErrorLevel 级别日志 仅在需要输出时格式化
+func (l *Logger) Errorf(format string, args ...any) {
+	l.logf(context.Background(), ErrorLevel, format, args...)
+}
+
+// Panicf 以 fmt.Sprintf 格式化输出 PanicLevel 级别日志 仅在需要输出时格式化
+func (l *Logger) Panicf(format string, args ...any) {
+	l.logf(context.Background(), PanicLevel, format, args...)
+}
+
+// Fatalf 以 fmt.Sprintf 格式化输出 FatalLevel 级别日志 仅在需要输出时格式化
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.logf(context.Background(), FatalLevel, format, args...)
+}
+
+// Tracew 以键值对输出 TraceLevel 级别日志
+func (l *Logger) Tracew(msg string, keysAndValues ...any) {
+	l.log(context.Background(), TraceLevel, msg, keysAndValues...)
+}
+
+// Debugw 以键值对输出 DebugLevel 级别日志
+func (l *Logger) Debugw(msg string, keysAndValues ...any) {
+	l.log(context.Background(), DebugLevel, msg, keysAndValues...)
+}
+
+// Infow 以键值对输出 InfoLevel 级别日志
+func (l *Logger) Infow(msg string, keysAndValues ...any) {
+	l.log(context.Background(), InfoLevel, msg, keysAndValues...)
+}
+
+// Warnw 以键值对输出 WarnLevel 级别日志
+func (l *Logger) Warnw(msg string, keysAndValues ...any) {
+	l.log(context.Background(), WarnLevel, msg, keysAndValues...)
+}
+
+// Errorw 以键值对输出 ErrorLevel 级别日志
+func (l *Logger) Errorw(msg string, keysAndValues ...any) {
+	l.log(context.Background(), ErrorLevel, msg, keysAndValues...)
+}
+
+// Panicw 以键值对输出 PanicLevel 级别日志
+func (l *Logger) Panicw(msg string, keysAndValues ...any) {
+	l.log(context.Background(), PanicLevel, msg, keysAndValues...)
+}
+
+// Fatalw 以键值对输出 FatalLevel 级别日志
+func (l *Logger) Fatalw(msg string, keysAndValues ...any) {
+	l.log(context.Background(), FatalLevel, msg, keysAndValues...)
+}
+
+// Log 输出任意级别日志 支持自定义日志级别
+func (l *Logger) Log(ctx context.Context, level LogLevel, msg string, args ...any) {
+	l.log(ctx, level, msg, args...)
+}
+
+// LogFields 以Fields输出任意级别日志 支持自定义日志级别
+func (l *Logger) LogFields(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	l.logFields(ctx, level, msg, fields...)
+}
+
 // Enabled 日志是否需要最终输出
 func (l *Logger) Enabled(ctx context.Context, level LogLevel) bool {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	// context 携带的日志级别覆盖由 LogHandler 处理
 	return l.handler.Enabled(ctx, level)
 }
 
@@ -110,8 +235,27 @@ func (l *Logger) log(ctx context.Context, level LogLevel, msg string, args ...an
 	var pcs [1]uintptr
 	// runtime.Callers. this function, this function's Caller
 	runtime.Callers(3, pcs[:])
-	entry := NewLogEntry(time.Now(), level, msg, pcs[0])
-	entry.AddArgs(args...)
+	entry := NewLogEntry(clockNow(l.clock), level, msg, pcs[0])
+	entry.AppendFields(l.fields...)
+	entry.addArgs(l.args, args...)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	_ = l.handler.LogRecord(ctx, entry)
+}
+
+// logf 格式化记录日志 Enabled 之后才执行 fmt.Sprintf
+func (l *Logger) logf(ctx context.Context, level LogLevel, format string, args ...any) {
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	// runtime.Callers. this function, this function's Caller
+	runtime.Callers(3, pcs[:])
+	entry := NewLogEntry(clockNow(l.clock), level, fmt.Sprintf(format, args...), pcs[0])
+	entry.AppendFields(l.fields...)
 
 	if ctx == nil {
 		ctx = context.Background()
@@ -129,7 +273,8 @@ func (l *Logger) logFields(ctx context.Context, level LogLevel, msg string, args
 	// runtime.Callers. this function, this function's Caller
 	runtime.Callers(3, pcs[:])
 
-	entry := NewLogEntry(time.Now(), level, msg, pcs[0])
+	entry := NewLogEntry(clockNow(l.clock), level, msg, pcs[0])
+	entry.AppendFields(l.fields...)
 	entry.AppendFields(args...)
 	if ctx == nil {
 		ctx = context.Background()
@@ -138,6 +283,18 @@ func (l *Logger) logFields(ctx context.Context, level LogLevel, msg string, args
 	_ = l.handler.LogRecord(ctx, entry)
 }
 
+// logFieldsPC 使用指定调用栈记录日志
+func (l *Logger) logFieldsPC(ctx context.Context, level LogLevel, pc uintptr, msg string, args ...LogField) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	entry := NewLogEntry(clockNow(l.clock), level, msg, pc)
+	entry.AppendFields(l.fields...)
+	entry.AppendFields(args...)
+
+	_ = l.handler.LogRecord(ctx, entry)
+}
+
 // Close 关闭日志器
 func (l *Logger) Close() error {
 	return l.handler.Close()
@@ -0,0 +1,63 @@
+package gslog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLoggerSugaredMethods(t *testing.T) {
+	tests := []struct {
+		name string
+		// 调用日志方法 返回调用所在行
+		log  func(logger *Logger) int
+		want string
+	}{
+		{"Tracef", func(l *Logger) int { l.Tracef("n=%d s=%q", 1, "a"); return currentLine() }, `"n=1 s=\"a\""`},
+		{"Debugf", func(l *Logger) int { l.Debugf("%05.1f%%", 3.14159); return currentLine() }, "003.1%"},
+		{"Infof", func(l *Logger) int { l.Infof("plain"); return currentLine() }, "plain"},
+		{"Warnf", func(l *Logger) int { l.Warnf("%v-%v", []int{1}, nil); return currentLine() }, "[1]-<nil>"},
+		{"Errorf", func(l *Logger) int { l.Errorf("%-4s|%x", "ab", 255); return currentLine() }, "ab  |ff"},
+		{"Panicf", func(l *Logger) int { l.Panicf("%[2]s %[1]s", "a", "b"); return currentLine() }, "b a"},
+		{"Fatalf", func(l *Logger) int { l.Fatalf("%s", "fatal"); return currentLine() }, "fatal"},
+		{"Tracew", func(l *Logger) int { l.Tracew("kv", "k", "v"); return currentLine() }, "kv k=v"},
+		{"Debugw", func(l *Logger) int { l.Debugw("slice", "ids", []int{1, 2}, "n", 3); return currentLine() }, "slice ids=[1, 2] n=3"},
+		{"Infow", func(l *Logger) int { l.Infow("odd", "k", 1, "dangling"); return currentLine() }, "odd k=1 !BADKEY[2]=dangling"},
+		{"Warnw", func(l *Logger) int { l.Warnw("bad key", 1, "v"); return currentLine() }, "bad key !BADKEY[0]=1 !BADKEY[1]=v"},
+		{"Errorw", func(l *Logger) int { l.Errorw("field", String("s", "v"), "k", true); return currentLine() }, "field s=v k=true"},
+		{"Panicw", func(l *Logger) int { l.Panicw("empty"); return currentLine() }, "empty"},
+		{"Fatalw", func(l *Logger) int { l.Fatalw("last", "k"); return currentLine() }, "last !BADKEY[0]=k"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bufferWriteSyncer{}
+			logger := NewLogger(NewTextHandlerWithOptions(writer, WithLevel(TraceLevel), WithTextFlag(LTextFile|LTextLogLevel)))
+			line := tt.log(logger)
+
+			// 方法名去掉 f/w 后缀即日志级别 调用位置为用户代码 而不是 Logger 内部
+			got := writer.String()
+			prefix := "[" + tt.name[:len(tt.name)-1] + "] "
+			suffix := fmt.Sprintf("/logger_test.go:%d %s \n", line, tt.want)
+			if !strings.HasPrefix(got, prefix) || !strings.HasSuffix(got, suffix) {
+				t.Errorf("output %q, want %q...%q", got, prefix, suffix)
+			}
+		})
+	}
+}
+
+func TestLoggerSugaredDisabled(t *testing.T) {
+	writer := &bufferWriteSyncer{}
+	logger := NewLogger(NewTextHandlerWithOptions(writer, WithLevel(InfoLevel)))
+
+	// 不需要输出时不格式化
+	calls := 0
+	logger.Debugf("%v", countingStringer{&calls})
+	logger.Debugw("msg", "k", countingStringer{&calls})
+	if calls != 0 || writer.String() != "" {
+		t.Errorf("disabled entry formatted %d times, output %q", calls, writer.String())
+	}
+	logger.Infof("%v", countingStringer{&calls})
+	if calls != 1 {
+		t.Errorf("enabled entry formatted %d times, want 1", calls)
+	}
+}
@@ -169,6 +169,86 @@ func FatalFieldsContext(ctx context.Context, msg string, args ...LogField) {
 	Default().logFields(ctx, FatalLevel, msg, args...)
 }
 
+// Tracef 以 fmt.Sprintf 格式化输出 TraceLevel 级别日志 仅在需要输出时格式化
+func Tracef(format string, args ...any) {
+	Default().logf(context.Background(), TraceLevel, format, args...)
+}
+
+// Debugf 以 fmt.Sprintf 格式化输出 DebugLevel 级别日志 仅在需要输出时格式化
+func Debugf(format string, args ...any) {
+	Default().logf(context.Background(), DebugLevel, format, args...)
+}
+
+// Infof 以 fmt.Sprintf 格式化输出 InfoLevel 级别日志 仅在需要输出时格式化
+func Infof(format string, args ...any) {
+	Default().logf(context.Background(), InfoLevel, format, args...)
+}
+
+// Warnf 以 fmt.Sprintf 格式化输出 WarnLevel 级别日志 仅在需要输出时格式化
+func Warnf(format string, args ...any) {
+	Default().logf(context.Background(), WarnLevel, format, args...)
+}
+
+// Errorf 以 fmt.Sprintf 格式化输出 ErrorLevel 级别日志 仅在需要输出时格式化
+func Errorf(format string, args ...any) {
+	Default().logf(context.Background(), ErrorLevel, format, args...)
+}
+
+// Panicf 以 fmt.Sprintf 格式化输出 PanicLevel 级别日志 仅在需要输出时格式化
+func Panicf(format string, args ...any) {
+	Default().logf(context.Background(), PanicLevel, format, args...)
+}
+
+// Fatalf 以 fmt.Sprintf 格式化输出 FatalLevel 级别日志 仅在需要输出时格式化
+func Fatalf(format string, args ...any) {
+	Default().logf(context.Background(), FatalLevel, format, args...)
+}
+
+// Tracew 以键值对输出 TraceLevel 级别日志
+func Tracew(msg string, keysAndValues ...any) {
+	Default().log(context.Background(), TraceLevel, msg, keysAndValues...)
+}
+
+// Debugw 以键值对输出 DebugLevel 级别日志
+func Debugw(msg string, keysAndValues ...any) {
+	Default().log(context.Background(), DebugLevel, msg, keysAndValues...)
+}
+
+// Infow 以键值对输出 InfoLevel 级别日志
+func Infow(msg string, keysAndValues ...any) {
+	Default().log(context.Background(), InfoLevel, msg, keysAndValues...)
+}
+
+// Warnw 以键值对输出 WarnLevel 级别日志
+func Warnw(msg string, keysAndValues ...any) {
+	Default().log(context.Background(), WarnLevel, msg, keysAndValues...)
+}
+
+// Errorw 以键值对输出 ErrorLevel 级别日志
+func Errorw(msg string, keysAndValues ...any) {
+	Default().log(context.Background(), ErrorLevel, msg, keysAndValues...)
+}
+
+// Panicw 以键值对输出 PanicLevel 级别日志
+func Panicw(msg string, keysAndValues ...any) {
+	Default().log(context.Background(), PanicLevel, msg, keysAndValues...)
+}
+
+// Fatalw 以键值对输出 FatalLevel 级别日志
+func Fatalw(msg string, keysAndValues ...any) {
+	Default().log(context.Background(), FatalLevel, msg, keysAndValues...)
+}
+
+// Log 输出任意级别日志 支持自定义日志级别
+func Log(ctx context.Context, level LogLevel, msg string, args ...any) {
+	Default().log(ctx, level, msg, args...)
+}
+
+// LogFields 以Fields输出任意级别日志 支持自定义日志级别
+func LogFields(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	Default().logFields(ctx, level, msg, fields...)
+}
+
 // Sync 同步缓冲日志
 func Sync() error {
 	return Default().Sync()